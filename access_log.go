@@ -0,0 +1,209 @@
+package orz
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// accessLogFields 访问日志可选的字段
+var accessLogFields = []string{
+	"method", "route", "path", "query", "status", "latency", "bytes_in", "bytes_out",
+	"ip", "user_agent", "referer", "host", "protocol", "request_id", "principal",
+}
+
+// defaultAccessLogFields 未配置 server.access_log.fields 时记录的字段
+var defaultAccessLogFields = []string{
+	"request_id", "method", "route", "status", "latency", "bytes_in", "bytes_out", "ip", "principal",
+}
+
+// accessLog 当前生效的访问日志配置，配置重新加载时整体替换
+type accessLog struct {
+	config accessLogSettings
+	logger *zap.Logger // 写入单独文件的日志器，为空时使用应用日志器
+	writer rotatingWriter
+}
+
+type accessLogSettings struct {
+	fields        []string
+	skipPaths     []string
+	slowThreshold time.Duration
+	sampling      float64
+}
+
+type accessLogState struct {
+	current atomic.Pointer[accessLog]
+}
+
+// AccessLog 返回按 server.access_log 记录访问日志的中间件，EnableHTTP 会自动注册
+// 每个请求记录一条日志：5xx 为 error 级别，超过 slow_threshold 为 warn 级别，其余为 info 级别并按 sampling 采样
+func (a *App) AccessLog() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			current := a.accessLog.current.Load()
+			if current == nil || skipAccessLog(c, current.config.skipPaths) {
+				return next(c)
+			}
+
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				// 先交给错误处理器写入响应，才能记录最终的状态码与响应大小
+				c.Echo().HTTPErrorHandler(c, err)
+			}
+			latency := time.Since(start)
+
+			resp, status := echo.ResolveResponseStatus(c.Response(), err)
+			slow := current.config.slowThreshold > 0 && latency >= current.config.slowThreshold
+			if status < http.StatusBadRequest && !slow && !sampled(current.config.sampling) {
+				return nil
+			}
+
+			var size int64
+			if resp != nil {
+				size = resp.Size
+			}
+			fields := accessLogValues(c, current.config.fields, status, latency, size)
+			if err != nil {
+				fields = append(fields, zap.Error(err))
+			}
+
+			logger := current.logger
+			if logger == nil {
+				logger = a.Logger().Named("http").Named("access")
+			}
+			switch {
+			case status >= http.StatusInternalServerError:
+				logger.Error("http request", fields...)
+			case slow:
+				logger.Warn("slow http request", fields...)
+			default:
+				logger.Info("http request", fields...)
+			}
+			return nil
+		}
+	}
+}
+
+// configureAccessLog 按配置启用或更新访问日志，替换后关闭之前打开的日志文件
+func (a *App) configureAccessLog(config AccessLogConfig) {
+	var next *accessLog
+	if config.Enabled {
+		fields := config.Fields
+		if len(fields) == 0 {
+			fields = defaultAccessLogFields
+		}
+		next = &accessLog{config: accessLogSettings{
+			fields:        normalizeAccessLogFields(fields),
+			skipPaths:     config.SkipPaths,
+			slowThreshold: config.SlowThreshold,
+			sampling:      config.Sampling,
+		}}
+		if config.Filename != "" {
+			next.logger, next.writer = a.newAccessLogFile(config)
+		}
+	}
+
+	if previous := a.accessLog.current.Swap(next); previous != nil && previous.writer != nil {
+		_ = previous.writer.Close()
+	}
+}
+
+// newAccessLogFile 创建写入单独文件的 JSON 日志器，按大小滚动
+func (a *App) newAccessLogFile(config AccessLogConfig) (*zap.Logger, rotatingWriter) {
+	writer := &lumberjack.Logger{
+		Filename:  config.Filename,
+		MaxSize:   getMaxSize(config.MaxSize),
+		MaxAge:    getMaxAge(config.MaxAge),
+		Compress:  config.Compress,
+		LocalTime: true,
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = "time"
+	encoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05.000")
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(writer), zapcore.InfoLevel)
+	return a.withSecretRedaction(zap.New(core)), writer
+}
+
+func normalizeAccessLogFields(fields []string) []string {
+	normalized := make([]string, 0, len(fields))
+	for _, field := range fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !slices.Contains(normalized, field) {
+			normalized = append(normalized, field)
+		}
+	}
+	return normalized
+}
+
+// skipAccessLog 请求路径或路由模板在 skip_paths 中时不记录
+func skipAccessLog(c *echo.Context, skipPaths []string) bool {
+	path, route := c.Request().URL.Path, c.Path()
+	for _, skip := range skipPaths {
+		if skip == path || skip == route {
+			return true
+		}
+	}
+	return false
+}
+
+func sampled(rate float64) bool {
+	return rate >= 1 || rate > 0 && rand.Float64() < rate
+}
+
+func accessLogValues(c *echo.Context, names []string, status int, latency time.Duration, size int64) []zap.Field {
+	req := c.Request()
+	fields := make([]zap.Field, 0, len(names)+1)
+	for _, name := range names {
+		switch name {
+		case "method":
+			fields = append(fields, zap.String(name, req.Method))
+		case "route":
+			route := c.Path()
+			if route == "" {
+				route = req.URL.Path
+			}
+			fields = append(fields, zap.String(name, route))
+		case "path":
+			fields = append(fields, zap.String(name, req.URL.Path))
+		case "query":
+			fields = append(fields, zap.String(name, req.URL.RawQuery))
+		case "status":
+			fields = append(fields, zap.Int(name, status))
+		case "latency":
+			fields = append(fields, zap.Duration(name, latency))
+		case "bytes_in":
+			fields = append(fields, zap.Int64(name, max(req.ContentLength, 0)))
+		case "bytes_out":
+			fields = append(fields, zap.Int64(name, size))
+		case "ip":
+			fields = append(fields, zap.String(name, c.RealIP()))
+		case "user_agent":
+			fields = append(fields, zap.String(name, req.UserAgent()))
+		case "referer":
+			fields = append(fields, zap.String(name, req.Referer()))
+		case "host":
+			fields = append(fields, zap.String(name, req.Host))
+		case "protocol":
+			fields = append(fields, zap.String(name, req.Proto))
+		case "request_id":
+			if id := RequestIDFrom(req.Context()); id != "" {
+				fields = append(fields, zap.String(name, id))
+			}
+		case "principal":
+			if principal := PrincipalFrom(req.Context()); principal != "" {
+				fields = append(fields, zap.String(name, principal))
+			}
+		}
+	}
+	return fields
+}
@@ -0,0 +1,154 @@
+package orz
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"go.uber.org/zap/zapcore"
+)
+
+func serveAccessLogRequests(app *App, paths ...string) {
+	for _, path := range paths {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader("hello"))
+		req.RemoteAddr = "203.0.113.9:1234"
+		app.GetEcho().ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
+func newAccessLogApp(t *testing.T, content string) (*App, string, func() []map[string]any) {
+	t.Helper()
+
+	app, path, logs := newReloadableApp(t, content)
+	app.EnableHTTP()
+	e := app.GetEcho()
+	e.POST("/users/:id", func(c *echo.Context) error {
+		SetPrincipal(c, "user-42")
+		return c.String(http.StatusOK, "created")
+	})
+	e.POST("/fail", func(c *echo.Context) error {
+		return errors.New("boom")
+	})
+
+	entries := func() []map[string]any {
+		var result []map[string]any
+		for _, entry := range logs.All() {
+			if entry.LoggerName != "http.access" {
+				continue
+			}
+			fields := entry.ContextMap()
+			fields["msg"] = entry.Message
+			fields["level"] = entry.Level.String()
+			result = append(result, fields)
+		}
+		return result
+	}
+	return app, path, entries
+}
+
+func TestAccessLogRecordsRequests(t *testing.T) {
+	app, _, entries := newAccessLogApp(t, "server:\n  access_log:\n    enabled: true\n    slow_threshold: 0\n")
+
+	serveAccessLogRequests(app, "/users/7", "/healthz", "/fail", "/missing")
+
+	logged := entries()
+	if len(logged) != 3 {
+		t.Fatalf("expected 3 access log entries with /healthz skipped, got %v", logged)
+	}
+
+	ok := logged[0]
+	expected := map[string]any{
+		"msg":       "http request",
+		"level":     zapcore.InfoLevel.String(),
+		"method":    http.MethodPost,
+		"route":     "/users/:id",
+		"status":    int64(http.StatusOK),
+		"bytes_in":  int64(5),
+		"bytes_out": int64(len("created")),
+		"ip":        "203.0.113.9",
+		"principal": "user-42",
+	}
+	for key, value := range expected {
+		if ok[key] != value {
+			t.Fatalf("expected %s=%v, got %v", key, value, ok)
+		}
+	}
+	if ok["request_id"] == "" || ok["latency"] == nil {
+		t.Fatalf("expected request_id and latency fields, got %v", ok)
+	}
+
+	if failed := logged[1]; failed["level"] != zapcore.ErrorLevel.String() || failed["status"] != int64(http.StatusInternalServerError) || failed["error"] != "boom" {
+		t.Fatalf("unexpected entry for failed request: %v", failed)
+	}
+	if missing := logged[2]; missing["status"] != int64(http.StatusNotFound) || missing["route"] != "/missing" {
+		t.Fatalf("unexpected entry for unknown route: %v", missing)
+	}
+}
+
+func TestAccessLogSamplingAndReload(t *testing.T) {
+	app, path, entries := newAccessLogApp(t, "server:\n  access_log:\n    enabled: true\n    sampling: 0\n    fields: [status, path]\n")
+
+	serveAccessLogRequests(app, "/users/7", "/missing")
+	logged := entries()
+	if len(logged) != 1 || logged[0]["status"] != int64(http.StatusNotFound) {
+		t.Fatalf("expected only the failed request to bypass sampling, got %v", logged)
+	}
+	if _, ok := logged[0]["method"]; ok || logged[0]["path"] != "/missing" {
+		t.Fatalf("expected only configured fields, got %v", logged[0])
+	}
+
+	writeConfigFile(t, path, "server:\n  access_log:\n    enabled: false\n")
+	if err := app.ReloadConfig(); err != nil {
+		t.Fatalf("ReloadConfig returned error: %v", err)
+	}
+	serveAccessLogRequests(app, "/missing")
+	if len(entries()) != 1 {
+		t.Fatalf("expected access log to be disabled after reload, got %v", entries())
+	}
+}
+
+func TestAccessLogWritesSeparateFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "logs", "access.log")
+	app, _, entries := newAccessLogApp(t, "server:\n  access_log:\n    enabled: true\n    filename: "+file+"\n")
+	defer app.configureAccessLog(AccessLogConfig{})
+
+	serveAccessLogRequests(app, "/users/7")
+	if len(entries()) != 0 {
+		t.Fatalf("expected access log to bypass the application logger, got %v", entries())
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatalf("expected access log file: %v", err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		t.Fatal("expected an access log line")
+	}
+	var line map[string]any
+	if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+		t.Fatalf("expected JSON access log line, got %q: %v", scanner.Text(), err)
+	}
+	if line["route"] != "/users/:id" || line["status"] != float64(http.StatusOK) {
+		t.Fatalf("unexpected access log line %v", line)
+	}
+}
+
+func TestAccessLogConfigValidation(t *testing.T) {
+	v := NewValidation()
+	AccessLogConfig{Fields: []string{"status", "cookie"}, Sampling: 1.5, SlowThreshold: -1}.ValidateConfig(v)
+	err := v.Err()
+	for _, expected := range []string{"fields[1]: must be one of", "sampling: must be between 0 and 1", "slow_threshold: must not be negative"} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected error to contain %q, got %v", expected, err)
+		}
+	}
+}
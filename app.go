@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"os/signal"
+	"sort"
 	"sync"
 	"syscall"
 	"time"
@@ -22,22 +23,33 @@ type Application interface {
 
 // App 应用容器
 type App struct {
-	logger        *zap.Logger
-	database      *gorm.DB
-	echo          *echo.Echo
-	configManager *ConfigManager
-	ctx           context.Context
-	cancel        context.CancelFunc
+	logger           *zap.Logger
+	logLevels        *logLevels     // 由配置创建日志器时可在运行时调整
+	logWriter        rotatingWriter // 由配置创建日志器时写入的日志文件
+	database         *gorm.DB
+	databases        map[string]*gorm.DB
+	echo             *echo.Echo
+	configManager    *ConfigManager
+	configValidators []appConfigValidator
+	ctx              context.Context
+	cancel           context.CancelFunc
+	lifecycle        lifecycle
+	workers          workerGroup
+	health           health
+	container        container
+	models           modelRegistry
+	commands         commandRegistry
+	configWatch      configWatch
+	appConfigs       appConfigCache
+	accessLog        accessLogState
 }
 
 // NewApp 创建新的应用
 func NewApp() *App {
-	ctx, cancel := context.WithCancel(context.Background())
-	return &App{
-		configManager: NewConfigManager(),
-		ctx:           ctx,
-		cancel:        cancel,
-	}
+	app := &App{configManager: NewConfigManager()}
+	// 应用上下文携带 App，使 LoggerFrom 在请求之外回退到应用日志器
+	app.ctx, app.cancel = context.WithCancel(context.WithValue(context.Background(), appContextKey, app))
+	return app
 }
 
 // EnableLogger 根据配置初始化日志器
@@ -47,25 +59,52 @@ func (a *App) EnableLogger() error {
 		return fmt.Errorf("config not loaded")
 	}
 
-	logger := NewLoggerFromConfig(config.Log)
-	a.SetLogger(logger)
+	if filename := logFilename(config.Log, time.Now()); filename != "" {
+		if err := ensureDir(filename); err != nil {
+			return fmt.Errorf("failed to create log directory: %w", err)
+		}
+	}
+
+	logger, levels, writer := newLoggerFromConfig(config.Log)
+	a.SetLogger(a.withSecretRedaction(logger))
+	a.logLevels = levels
+	a.logWriter = writer
 	return nil
 }
 
 // EnableDatabase 启用数据库
+// 连接 database 块描述的默认数据库，以及 databases 中的所有具名数据库
 func (a *App) EnableDatabase() error {
 	config := a.GetConfig()
-	if config == nil || !config.Database.Enabled {
+	if config == nil || (!config.Database.Enabled && len(config.Databases) == 0) {
 		return fmt.Errorf("database not enabled in config")
 	}
 
-	log := a.Logger()
-	db, err := ConnectDatabaseWithLogger(config.Database, log)
-	if err != nil {
-		return fmt.Errorf("failed to connect database: %w", err)
+	log := a.Logger().Named("gorm")
+	if config.Database.Enabled {
+		db, err := ConnectDatabaseWithLogger(config.Database, log)
+		if err != nil {
+			return fmt.Errorf("failed to connect database: %w", err)
+		}
+		a.SetDatabase(db)
+	}
+
+	names := make([]string, 0, len(config.Databases))
+	for name := range config.Databases {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	a.SetDatabase(db)
+	for _, name := range names {
+		if name == DefaultDatabaseName {
+			return fmt.Errorf("database name %q is reserved for the database block", name)
+		}
+		db, err := ConnectDatabaseWithLogger(config.Databases[name], log)
+		if err != nil {
+			return fmt.Errorf("failed to connect database %q: %w", name, err)
+		}
+		a.SetDatabaseNamed(name, db)
+	}
 	return nil
 }
 
@@ -75,9 +114,13 @@ func (a *App) EnableHTTP() {
 
 	if config := a.GetConfig(); config != nil {
 		e.IPExtractor = NewIPExtractor(config.Server.IPExtractor, config.Server.IPTrustList)
+		a.registerHealthRoutes(e, config.Server.Health)
+		a.registerAdminRoutes(e, config.Server.Admin)
+		a.configureAccessLog(config.Server.AccessLog)
 	}
 
 	ensureDirectIPExtractor(e)
+	e.Use(a.RequestLogger(), a.AccessLog())
 
 	a.SetEcho(e)
 }
@@ -87,6 +130,18 @@ func (a *App) SetDatabase(db *gorm.DB) {
 	a.database = db
 }
 
+// SetDatabaseNamed 设置具名数据库连接，名称为 default 时等同于 SetDatabase
+func (a *App) SetDatabaseNamed(name string, db *gorm.DB) {
+	if name == "" || name == DefaultDatabaseName {
+		a.SetDatabase(db)
+		return
+	}
+	if a.databases == nil {
+		a.databases = make(map[string]*gorm.DB)
+	}
+	a.databases[name] = db
+}
+
 // SetEcho 设置 Echo 实例
 func (a *App) SetEcho(e *echo.Echo) {
 	a.echo = e
@@ -104,6 +159,8 @@ func (a *App) Logger() *zap.Logger {
 // SetLogger 设置日志器
 func (a *App) SetLogger(logger *zap.Logger) {
 	a.logger = logger
+	a.logLevels = nil
+	a.logWriter = nil
 }
 
 // GetDatabase 获取数据库连接
@@ -111,6 +168,14 @@ func (a *App) GetDatabase() *gorm.DB {
 	return a.database
 }
 
+// GetDatabaseNamed 获取具名数据库连接，名称为空或 default 时返回默认数据库
+func (a *App) GetDatabaseNamed(name string) *gorm.DB {
+	if name == "" || name == DefaultDatabaseName {
+		return a.database
+	}
+	return a.databases[name]
+}
+
 // GetEcho 获取Echo实例
 func (a *App) GetEcho() *echo.Echo {
 	return a.echo
@@ -123,16 +188,19 @@ func (a *App) Context() context.Context {
 
 // LoadConfigFromFile 从文件加载配置
 func (a *App) LoadConfigFromFile(configPath string) error {
+	defer a.appConfigs.reset()
 	return a.configManager.LoadFromFile(configPath)
 }
 
 // LoadConfigFromBytes 从字节数组加载配置
 func (a *App) LoadConfigFromBytes(data []byte) error {
+	defer a.appConfigs.reset()
 	return a.configManager.LoadFromBytes(data)
 }
 
 // LoadConfigFromMap 从Map加载配置
 func (a *App) LoadConfigFromMap(data map[string]interface{}) error {
+	defer a.appConfigs.reset()
 	return a.configManager.LoadFromMap(data)
 }
 
@@ -143,15 +211,26 @@ func (a *App) GetConfig() *Config {
 
 // Run 运行应用
 func (a *App) Run() error {
+	if err := a.runStartHooks(); err != nil {
+		a.cancel()
+		return errors.Join(err, a.runStopHooks())
+	}
+
+	a.startWorkers()
+
+	var err error
 	// 获取Echo实例
-	e := a.GetEcho()
-	if e == nil {
+	if e := a.GetEcho(); e == nil {
 		a.Logger().Info("no HTTP server configured, running in daemon mode")
-		return a.runDaemon()
+		err = a.runDaemon()
+	} else {
+		// 启动HTTP服务器
+		err = a.runHTTPServer(e)
 	}
 
-	// 启动HTTP服务器
-	return a.runHTTPServer(e)
+	// HTTP 服务优雅关闭后再停止后台任务，最后执行停止钩子
+	a.cancel()
+	return errors.Join(err, a.stopWorkers(), a.workerFailure(), a.runStopHooks())
 }
 
 // runHTTPServer 运行HTTP服务器
@@ -162,10 +241,23 @@ func (a *App) runHTTPServer(e *echo.Echo) error {
 	serverCtx, stopServer := context.WithCancel(context.Background())
 	defer stopServer()
 
+	// 获取服务器配置
+	config := a.GetConfig()
+	addr := ":8080" // 默认端口
+	var drainDelay time.Duration
+	if config != nil {
+		if config.Server.Addr != "" {
+			addr = config.Server.Addr
+		}
+		drainDelay = config.Server.Health.DrainDelay
+	}
+
 	var shutdownOnce sync.Once
 	shutdown := func() {
 		shutdownOnce.Do(func() {
 			a.Logger().Info("shutting down server...")
+			// 先将就绪检查置为失败，让负载均衡在关闭连接前摘除流量
+			a.beginDraining(drainDelay)
 			a.cancel()
 			stopServer()
 		})
@@ -181,13 +273,6 @@ func (a *App) runHTTPServer(e *echo.Echo) error {
 		}
 	}()
 
-	// 获取服务器配置
-	config := a.GetConfig()
-	addr := ":8080" // 默认端口
-	if config != nil && config.Server.Addr != "" {
-		addr = config.Server.Addr
-	}
-
 	a.Logger().Info("starting server", zap.String("addr", addr))
 
 	startConfig := echo.StartConfig{
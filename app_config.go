@@ -0,0 +1,215 @@
+package orz
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// appConfigKey 缓存键，同一类型在不同路径下分别缓存
+type appConfigKey struct {
+	typ  reflect.Type
+	path string
+}
+
+type appConfigCache struct {
+	mu     sync.Mutex
+	values map[appConfigKey]any
+}
+
+func (c *appConfigCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values = nil
+}
+
+// AppConfigAs 将 app 配置段或其中的子路径解析为 T，例如 AppConfigAs[PaymentConfig](app, "payments")
+// 解析规则与 GetConfig 相同：支持 mapstructure 标签、时长字符串以及忽略大小写和下划线的键名匹配。
+// 配置中缺失的字段使用 default 标签的值，环境变量 [<PREFIX>_]APP_<PATH>_<FIELD> 可以覆盖任意嵌套字段。
+// 结果会被缓存，直到配置重新加载。
+func AppConfigAs[T any](app *App, path ...string) (T, error) {
+	key := appConfigKey{typ: reflect.TypeFor[T](), path: strings.Join(path, ".")}
+
+	app.appConfigs.mu.Lock()
+	defer app.appConfigs.mu.Unlock()
+
+	if value, ok := app.appConfigs.values[key]; ok {
+		return value.(T), nil
+	}
+
+	var result T
+	config, err := app.configManager.Decode()
+	if err != nil {
+		return result, err
+	}
+	if err := decodeAppConfig(config.App, key.path, app.configManager, &result); err != nil {
+		return result, fmt.Errorf("failed to decode %s: %w", joinConfigPath("app", key.path), err)
+	}
+
+	if app.appConfigs.values == nil {
+		app.appConfigs.values = make(map[appConfigKey]any)
+	}
+	app.appConfigs.values[key] = result
+	return result, nil
+}
+
+// BindAppConfig 声明 app.<path> 配置段的结构，target 为结构体指针，例如 &PaymentConfig{}
+// 每个字段都会绑定到对应的环境变量，即使配置文件中不存在该键，并出现在 config env 列表中
+func (a *App) BindAppConfig(path string, target any) {
+	a.configManager.BindEnv(joinConfigPath("app", path), target)
+}
+
+// WithAppConfig 声明 app 配置段的结构，参见 App.BindAppConfig
+func WithAppConfig(path string, target any) Option {
+	return func(f *Framework) error {
+		f.app.BindAppConfig(path, target)
+		return nil
+	}
+}
+
+// decodeAppConfig 依次应用 default 标签、配置值与环境变量，将 app 配置段解析到 target
+// 通过 BindAppConfig 绑定的键已由 cm 按命令行参数 > 环境变量 > 配置文件的优先级合并，不再用环境变量覆盖
+func decodeAppConfig(section AppConfig, path string, cm *ConfigManager, target any) error {
+	value := reflect.ValueOf(target).Elem()
+	if err := applyConfigDefaults(value); err != nil {
+		return err
+	}
+
+	input := lookupConfigPath(section, path)
+	overlay := configEnvOverlay{envPrefix: cm.EnvPrefix(), bound: cm.boundKeys()}
+	input = overlay.apply(input, value.Type(), joinConfigPath("app", path))
+	return decodeConfigValue(input, target)
+}
+
+// configField 参与解析的结构体字段
+type configField struct {
+	field reflect.StructField
+	key   string // 配置键名，squash 字段为空
+}
+
+// configFields 返回结构体中参与配置解析的字段，键名规则与 mapstructure 一致
+func configFields(t reflect.Type) []configField {
+	var fields []configField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(options, "squash") {
+			fields = append(fields, configField{field: field})
+			continue
+		}
+		if name == "" {
+			name = CamelToSnake(field.Name)
+		}
+		fields = append(fields, configField{field: field, key: name})
+	}
+	return fields
+}
+
+// isConfigSection 判断字段是否为需要递归处理的嵌套配置结构
+func isConfigSection(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != reflect.TypeFor[time.Time]()
+}
+
+// applyConfigDefaults 按 default 标签为零值字段设置默认值
+func applyConfigDefaults(value reflect.Value) error {
+	if !isConfigSection(value.Type()) {
+		return nil
+	}
+
+	for _, f := range configFields(value.Type()) {
+		field := value.FieldByIndex(f.field.Index)
+		if isConfigSection(f.field.Type) {
+			if err := applyConfigDefaults(field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		defaultValue, ok := f.field.Tag.Lookup("default")
+		if !ok || !field.IsZero() {
+			continue
+		}
+		if err := decodeConfigValue(defaultValue, field.Addr().Interface()); err != nil {
+			return fmt.Errorf("invalid default for field %s: %w", f.field.Name, err)
+		}
+	}
+	return nil
+}
+
+// configEnvOverlay 用环境变量覆盖未绑定的 app 配置键
+type configEnvOverlay struct {
+	envPrefix string
+	bound     map[string]bool // 已绑定环境变量的键，经 normalizeConfigKey 处理
+}
+
+// apply 用环境变量覆盖 t 中各字段对应的配置值，返回新的配置值，不修改原配置
+func (o configEnvOverlay) apply(input any, t reflect.Type, prefix string) any {
+	if !isConfigSection(t) {
+		if o.bound[normalizeConfigKey(prefix)] {
+			return input
+		}
+		if value, ok := os.LookupEnv(configEnvName(o.envPrefix, prefix)); ok {
+			return value
+		}
+		return input
+	}
+
+	result := make(map[string]any)
+	if values, ok := input.(map[string]any); ok {
+		result = cloneConfigMap(values)
+	}
+
+	for _, f := range configFields(t) {
+		if f.key == "" {
+			if overlay, ok := o.apply(result, f.field.Type, prefix).(map[string]any); ok {
+				result = overlay
+			}
+			continue
+		}
+
+		key := findConfigKey(result, f.key)
+		path := prefix + "." + f.key
+		if isConfigSection(f.field.Type) {
+			result[key] = o.apply(result[key], f.field.Type, path)
+			continue
+		}
+		if o.bound[normalizeConfigKey(path)] {
+			continue
+		}
+		if value, ok := os.LookupEnv(configEnvName(o.envPrefix, path)); ok {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// findConfigKey 返回 values 中与 key 匹配的已有键名，不存在时返回 key
+func findConfigKey(values map[string]any, key string) string {
+	for existing := range values {
+		if matchConfigName(existing, key) {
+			return existing
+		}
+	}
+	return key
+}
+
+func cloneConfigMap(values map[string]any) map[string]any {
+	result := make(map[string]any, len(values))
+	for key, value := range values {
+		if child, ok := value.(map[string]any); ok {
+			value = cloneConfigMap(child)
+		}
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,97 @@
+package orz
+
+import (
+	"testing"
+	"time"
+)
+
+type searchConfig struct {
+	Endpoint   string        `mapstructure:"endpoint" default:"http://localhost:9200"`
+	Timeout    time.Duration `mapstructure:"timeout" default:"5s"`
+	MaxRetries int           `default:"3"`
+	Indexes    []string      `default:"users,orders"`
+	Auth       searchAuth
+}
+
+type searchAuth struct {
+	Username string
+	APIKey   string `mapstructure:"api_key"`
+}
+
+func TestAppConfigAsDecodesWithHooksAndDefaults(t *testing.T) {
+	app := NewApp()
+	if err := app.LoadConfigFromBytes([]byte(`
+app:
+  search:
+    endpoint: "http://search:9200"
+    timeout: 2s
+    auth:
+      Username: elastic
+`)); err != nil {
+		t.Fatalf("LoadConfigFromBytes returned error: %v", err)
+	}
+
+	config, err := AppConfigAs[searchConfig](app, "search")
+	if err != nil {
+		t.Fatalf("AppConfigAs returned error: %v", err)
+	}
+	if config.Endpoint != "http://search:9200" || config.Timeout != 2*time.Second {
+		t.Fatalf("unexpected decoded values: %+v", config)
+	}
+	if config.MaxRetries != 3 || len(config.Indexes) != 2 || config.Indexes[1] != "orders" {
+		t.Fatalf("expected defaults to apply, got %+v", config)
+	}
+	if config.Auth.Username != "elastic" {
+		t.Fatalf("expected nested key to match case-insensitively, got %+v", config.Auth)
+	}
+}
+
+func TestAppConfigAsAppliesEnvOverrides(t *testing.T) {
+	t.Setenv("APP_SEARCH_MAX_RETRIES", "7")
+	t.Setenv("APP_SEARCH_AUTH_API_KEY", "from-env")
+
+	app := NewApp()
+	if err := app.LoadConfigFromMap(map[string]interface{}{
+		"app": map[string]interface{}{
+			"search": map[string]interface{}{"maxRetries": 1},
+		},
+	}); err != nil {
+		t.Fatalf("LoadConfigFromMap returned error: %v", err)
+	}
+
+	config, err := AppConfigAs[searchConfig](app, "search")
+	if err != nil {
+		t.Fatalf("AppConfigAs returned error: %v", err)
+	}
+	if config.MaxRetries != 7 || config.Auth.APIKey != "from-env" {
+		t.Fatalf("expected env overrides to apply, got %+v", config)
+	}
+	if app.GetConfig().App["search"].(map[string]any)["maxretries"] != 1 {
+		t.Fatal("expected env overlay not to modify the loaded config")
+	}
+}
+
+func TestAppConfigAsCachesUntilReload(t *testing.T) {
+	app, path, _ := newReloadableApp(t, "app:\n  search:\n    endpoint: http://a\n")
+
+	first, err := AppConfigAs[searchConfig](app, "search")
+	if err != nil {
+		t.Fatalf("AppConfigAs returned error: %v", err)
+	}
+
+	writeConfigFile(t, path, "app:\n  search:\n    endpoint: http://b\n")
+	if cached, _ := AppConfigAs[searchConfig](app, "search"); cached.Endpoint != first.Endpoint {
+		t.Fatalf("expected cached value before reload, got %q", cached.Endpoint)
+	}
+
+	if err := app.ReloadConfig(); err != nil {
+		t.Fatalf("ReloadConfig returned error: %v", err)
+	}
+	reloaded, err := AppConfigAs[searchConfig](app, "search")
+	if err != nil {
+		t.Fatalf("AppConfigAs returned error: %v", err)
+	}
+	if reloaded.Endpoint != "http://b" {
+		t.Fatalf("expected reloaded value, got %q", reloaded.Endpoint)
+	}
+}
@@ -0,0 +1,458 @@
+package orz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// redactedValue 替换敏感配置值
+const redactedValue = "******"
+
+// Command 自定义命令，与 serve 使用相同的配置、日志与数据库初始化
+type Command struct {
+	Name  string // 命令名称，例如 "reindex"
+	Usage string // 单行说明，显示在 help 中
+	Run   func(ctx context.Context, app *App, args []string) error
+}
+
+type commandRegistry struct {
+	mu       sync.Mutex
+	commands []Command
+}
+
+// builtinCommands 内置命令及说明，按 help 中的显示顺序排列
+var builtinCommands = []Command{
+	{Name: "serve", Usage: "start the application (default)"},
+	{Name: "migrate", Usage: "run migrations: migrate up | down [n] | status"},
+	{Name: "config", Usage: "configuration tools: config print [--origin] | env | schema | validate <file> | encrypt [value] | decrypt [value]"},
+	{Name: "routes", Usage: "list registered HTTP routes"},
+	{Name: "drivers", Usage: "list registered database drivers"},
+	{Name: "help", Usage: "show this help"},
+}
+
+// AddCommand 注册自定义命令，通过 Framework.Execute 调用
+// 名称为空、与内置命令或已注册命令重复时 panic
+func (a *App) AddCommand(command Command) {
+	if command.Name == "" {
+		panic("orz: AddCommand called with empty name")
+	}
+	if command.Run == nil {
+		panic(fmt.Sprintf("orz: command %q has nil Run", command.Name))
+	}
+
+	sameName := func(existing Command) bool { return existing.Name == command.Name }
+
+	a.commands.mu.Lock()
+	defer a.commands.mu.Unlock()
+	if slices.ContainsFunc(builtinCommands, sameName) || slices.ContainsFunc(a.commands.commands, sameName) {
+		panic(fmt.Sprintf("orz: command %q already registered", command.Name))
+	}
+	a.commands.commands = append(a.commands.commands, command)
+}
+
+func (a *App) command(name string) (Command, bool) {
+	a.commands.mu.Lock()
+	defer a.commands.mu.Unlock()
+	for _, command := range a.commands.commands {
+		if command.Name == name {
+			return command, true
+		}
+	}
+	return Command{}, false
+}
+
+// Execute 根据命令行参数执行命令，args 通常为 os.Args
+// 没有子命令时等同于 serve，即 Run。使用 WithFlags 时，子命令取自 WithFlags 解析配置参数后剩余的参数
+// NewFramework 只加载配置，Execute 按命令完成所需的初始化：config 与 drivers 不连接数据库，migrate 不自动执行迁移
+func (f *Framework) Execute(args []string) error {
+	program := "app"
+	if len(args) > 0 {
+		program = filepath.Base(args[0])
+		args = args[1:]
+	}
+	if f.flags != nil {
+		args = f.flags.Args()
+	}
+	if f.flagHelp {
+		if err := f.initializeTo(stageReady); err != nil {
+			return err
+		}
+		return f.printHelp(program)
+	}
+	if len(args) == 0 {
+		return f.Run()
+	}
+
+	name, args := args[0], args[1:]
+	if err := f.initializeTo(commandStage(name)); err != nil {
+		return err
+	}
+	switch name {
+	case "serve":
+		return f.Run()
+	case "help", "-h", "--help":
+		return f.printHelp(program)
+	}
+
+	ctx, stop := signal.NotifyContext(f.app.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	switch name {
+	case "migrate":
+		return f.executeMigrate(ctx, args)
+	case "config":
+		return f.executeConfig(args)
+	case "routes":
+		return f.printRoutes()
+	case "drivers":
+		return f.printDrivers()
+	}
+
+	command, ok := f.app.command(name)
+	if !ok {
+		return fmt.Errorf("unknown command %q, run %q for usage", name, program+" help")
+	}
+	if err := command.Run(ctx, f.app, args); err != nil {
+		return fmt.Errorf("command %q failed: %w", name, err)
+	}
+	return nil
+}
+
+// commandStage 返回命令所需的初始化阶段
+// config 与 drivers 只需要配置；migrate 只连接数据库，不自动执行迁移；
+// 其它命令（包括在 Configure 中注册的自定义命令）需要完整初始化
+func commandStage(name string) initStage {
+	switch name {
+	case "config", "drivers":
+		return stageConfig
+	case "migrate":
+		return stageDatabase
+	}
+	return stageReady
+}
+
+func (f *Framework) output() io.Writer {
+	if f.stdout != nil {
+		return f.stdout
+	}
+	return os.Stdout
+}
+
+func (f *Framework) input() io.Reader {
+	if f.stdin != nil {
+		return f.stdin
+	}
+	return os.Stdin
+}
+
+func (f *Framework) printHelp(program string) error {
+	f.app.commands.mu.Lock()
+	commands := append(append([]Command(nil), builtinCommands...), f.app.commands.commands...)
+	f.app.commands.mu.Unlock()
+
+	usage := "Usage: %s [command] [args]\n\nCommands:\n"
+	if f.flags != nil {
+		usage = "Usage: %s [flags] [command] [args]\n\nCommands:\n"
+	}
+
+	w := tabwriter.NewWriter(f.output(), 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, usage, program)
+	for _, command := range commands {
+		fmt.Fprintf(w, "  %s\t%s\n", command.Name, command.Usage)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if f.flags != nil {
+		_, err := fmt.Fprintf(f.output(), "\nFlags:\n%s", f.flags.FlagUsages())
+		return err
+	}
+	return nil
+}
+
+func (f *Framework) executeMigrate(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up | down [n] | status")
+	}
+
+	migrator, err := f.newMigrator()
+	if err != nil {
+		return err
+	}
+
+	out := f.output()
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Fprintf(out, "applied %d %s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(out, "no pending migrations")
+		}
+		return err
+	case "down":
+		n := 1
+		if len(args) > 1 {
+			if n, err = strconv.Atoi(args[1]); err != nil || n <= 0 {
+				return fmt.Errorf("invalid number of migrations to roll back: %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, n)
+		for _, migration := range reverted {
+			fmt.Fprintf(out, "reverted %d %s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(reverted) == 0 {
+			fmt.Fprintln(out, "no applied migrations")
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, status := range statuses {
+			state, appliedAt := "pending", "-"
+			if status.Applied {
+				state, appliedAt = "applied", status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down or status", args[0])
+	}
+}
+
+func (f *Framework) executeConfig(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: config print [--origin] | env | schema | validate <file> | encrypt [value] | decrypt [value]")
+	}
+
+	switch args[0] {
+	case "print":
+		return f.printConfig(args[1:])
+	case "env":
+		return f.printConfigEnv()
+	case "schema":
+		encoder := json.NewEncoder(f.output())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(f.app.configManager.JSONSchema())
+	case "validate":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: config validate <file>")
+		}
+		if err := f.app.ValidateConfigFile(args[1]); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(f.output(), "%s: ok\n", args[1])
+		return err
+	case "encrypt", "decrypt":
+		value, err := f.commandInput(args[1:])
+		if err != nil {
+			return err
+		}
+		convert := f.app.configManager.EncryptValue
+		if args[0] == "decrypt" {
+			convert = f.app.configManager.DecryptValue
+		}
+		result, err := convert(value)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(f.output(), result)
+		return err
+	default:
+		return fmt.Errorf("unknown config command %q, expected print, env, schema, validate, encrypt or decrypt", args[0])
+	}
+}
+
+// commandInput 返回唯一的命令参数，没有参数时从标准输入读取，避免明文留在 shell 历史中
+func (f *Framework) commandInput(args []string) (string, error) {
+	switch len(args) {
+	case 0:
+		data, err := io.ReadAll(f.input())
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	case 1:
+		return args[0], nil
+	default:
+		return "", fmt.Errorf("expected a single value, got %d arguments", len(args))
+	}
+}
+
+func (f *Framework) printConfig(args []string) error {
+	withOrigin := false
+	for _, arg := range args {
+		if arg != "--origin" {
+			return fmt.Errorf("unknown flag %q for config print", arg)
+		}
+		withOrigin = true
+	}
+
+	config := f.app.GetConfig()
+	if config == nil {
+		return fmt.Errorf("config not loaded")
+	}
+
+	var node yaml.Node
+	if err := node.Encode(config); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	redactNode(&node, f.app.configManager.RedactSecrets)
+	if withOrigin {
+		annotateOrigins(&node, "", f.app.configManager.Origin)
+	}
+
+	encoder := yaml.NewEncoder(f.output())
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	return encoder.Close()
+}
+
+func (f *Framework) printConfigEnv() error {
+	w := tabwriter.NewWriter(f.output(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tKEY\tTYPE\tDEFAULT\tDESCRIPTION")
+	for _, envVar := range f.app.configManager.EnvVars() {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", envVar.Name, envVar.Key, envVar.Type, envVar.Default, envVar.Usage)
+	}
+	return w.Flush()
+}
+
+func (f *Framework) printRoutes() error {
+	e := f.app.GetEcho()
+	if e == nil {
+		return fmt.Errorf("HTTP not enabled, use WithHTTP")
+	}
+
+	routes := e.Router().Routes()
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	w := tabwriter.NewWriter(f.output(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tNAME")
+	for _, route := range routes {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", route.Method, route.Path, route.Name)
+	}
+	return w.Flush()
+}
+
+func (f *Framework) printDrivers() error {
+	for _, driver := range RegisteredDatabaseDrivers() {
+		fmt.Fprintln(f.output(), driver)
+	}
+	return nil
+}
+
+// sensitiveKeyParts 键名包含这些片段的配置值会被隐藏
+var sensitiveKeyParts = []string{"password", "passwd", "secret", "token", "api_key", "apikey", "private_key", "credential"}
+
+// dsnCredentials 匹配 user:password@ 形式的 DSN，例如 MySQL 的 user:pass@tcp(host)/db
+var dsnCredentials = regexp.MustCompile(`^([^:/@]+):([^@]*)@`)
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactNode 隐藏 YAML 节点中的密码、令牌等敏感值，以及连接串中的密码
+// redactSecrets 隐藏其余值中出现的、通过 ${env:...}、${file:...} 引用解析出的敏感值
+func redactNode(node *yaml.Node, redactSecrets func(string) string) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			redactNode(child, redactSecrets)
+		}
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			if child.Kind == yaml.ScalarNode {
+				child.Value = redactSecrets(child.Value)
+				continue
+			}
+			redactNode(child, redactSecrets)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			if value.Kind != yaml.ScalarNode {
+				redactNode(value, redactSecrets)
+				continue
+			}
+			if value.Value == "" {
+				continue
+			}
+			switch {
+			case isSensitiveKey(key):
+				value.Value, value.Tag, value.Style = redactedValue, "!!str", 0
+			case strings.EqualFold(key, "url") || strings.EqualFold(key, "dsn"):
+				value.Value = redactSecrets(redactDSN(value.Value))
+			default:
+				value.Value = redactSecrets(value.Value)
+			}
+		}
+	}
+}
+
+// annotateOrigins 以行尾注释标注每个配置项的来源
+func annotateOrigins(node *yaml.Node, path string, origin func(key string) string) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			annotateOrigins(child, path, origin)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPath := key.Value
+			if path != "" {
+				childPath = path + "." + key.Value
+			}
+			if value.Kind == yaml.MappingNode {
+				annotateOrigins(value, childPath, origin)
+				continue
+			}
+			value.LineComment = origin(childPath)
+		}
+	}
+}
+
+// redactDSN 隐藏连接串中的密码
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" && u.User != nil {
+		// url.Redacted 使用 xxxxx 占位，统一替换为 redactedValue
+		return strings.Replace(u.Redacted(), ":xxxxx@", ":"+redactedValue+"@", 1)
+	}
+	return dsnCredentials.ReplaceAllString(dsn, "${1}:"+redactedValue+"@")
+}
@@ -0,0 +1,165 @@
+package orz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func newCommandFramework(t *testing.T, options ...Option) (*Framework, *bytes.Buffer) {
+	t.Helper()
+
+	options = append([]Option{
+		WithLogger(zap.NewNop()),
+		WithConfigMap(map[string]interface{}{
+			"database": map[string]interface{}{
+				"type": "mysql",
+				"url":  "root:hunter2@tcp(127.0.0.1:3306)/app",
+				"mysql": map[string]interface{}{
+					"password": "hunter2",
+				},
+			},
+			"app": map[string]interface{}{
+				"api_token": "abc123",
+				"name":      "demo",
+			},
+		}),
+	}, options...)
+
+	framework, err := NewFramework(options...)
+	if err != nil {
+		t.Fatalf("NewFramework returned error: %v", err)
+	}
+	out := &bytes.Buffer{}
+	framework.stdout = out
+	return framework, out
+}
+
+func TestExecuteRunsCustomCommand(t *testing.T) {
+	var gotArgs []string
+	framework, _ := newCommandFramework(t, WithApplication(NewSimpleApp(func(app *App) error {
+		app.AddCommand(Command{
+			Name:  "reindex",
+			Usage: "rebuild search index",
+			Run: func(ctx context.Context, app *App, args []string) error {
+				gotArgs = args
+				return nil
+			},
+		})
+		app.AddCommand(Command{
+			Name: "broken",
+			Run: func(ctx context.Context, app *App, args []string) error {
+				return errors.New("boom")
+			},
+		})
+		return nil
+	})))
+
+	if err := framework.Execute([]string{"svc", "reindex", "--full"}); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if len(gotArgs) != 1 || gotArgs[0] != "--full" {
+		t.Fatalf("unexpected command args: %v", gotArgs)
+	}
+
+	if err := framework.Execute([]string{"svc", "broken"}); err == nil || !strings.Contains(err.Error(), `command "broken" failed: boom`) {
+		t.Fatalf("expected wrapped command error, got %v", err)
+	}
+	if err := framework.Execute([]string{"svc", "unknown"}); err == nil || !strings.Contains(err.Error(), `unknown command "unknown"`) {
+		t.Fatalf("expected unknown command error, got %v", err)
+	}
+}
+
+func TestExecuteHelpListsCommands(t *testing.T) {
+	framework, out := newCommandFramework(t)
+	framework.app.AddCommand(Command{Name: "reindex", Usage: "rebuild search index", Run: func(context.Context, *App, []string) error { return nil }})
+
+	if err := framework.Execute([]string{"/usr/bin/svc", "help"}); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	for _, expected := range []string{"Usage: svc [command]", "serve", "migrate", "reindex", "rebuild search index"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Fatalf("expected help to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestAddCommandRejectsDuplicates(t *testing.T) {
+	app := NewApp()
+	run := func(context.Context, *App, []string) error { return nil }
+	app.AddCommand(Command{Name: "reindex", Run: run})
+
+	for _, name := range []string{"reindex", "migrate"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected AddCommand(%q) to panic", name)
+				}
+			}()
+			app.AddCommand(Command{Name: name, Run: run})
+		}()
+	}
+}
+
+func TestExecuteConfigPrintRedactsSecrets(t *testing.T) {
+	framework, out := newCommandFramework(t)
+
+	if err := framework.Execute([]string{"svc", "config", "print"}); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	printed := out.String()
+	if strings.Contains(printed, "hunter2") || strings.Contains(printed, "abc123") {
+		t.Fatalf("expected secrets to be redacted, got:\n%s", printed)
+	}
+	for _, expected := range []string{"root:******@tcp(127.0.0.1:3306)/app", "api_token: '******'", "name: demo"} {
+		if !strings.Contains(printed, expected) {
+			t.Fatalf("expected output to contain %q, got:\n%s", expected, printed)
+		}
+	}
+}
+
+func TestRedactDSN(t *testing.T) {
+	cases := map[string]string{
+		"postgres://app:s3cret@db:5432/app?sslmode=disable": "postgres://app:******@db:5432/app?sslmode=disable",
+		"root:pass@tcp(db:3306)/app":                        "root:******@tcp(db:3306)/app",
+		"file:test.db?cache=shared":                         "file:test.db?cache=shared",
+	}
+	for dsn, expected := range cases {
+		if got := redactDSN(dsn); got != expected {
+			t.Fatalf("redactDSN(%q) = %q, want %q", dsn, got, expected)
+		}
+	}
+}
+
+func TestExecuteRoutesAndDrivers(t *testing.T) {
+	framework, out := newCommandFramework(t, WithHTTP(), WithApplication(NewSimpleApp(func(app *App) error {
+		app.GetEcho().GET("/users", func(c *echo.Context) error { return nil })
+		return nil
+	})))
+
+	if err := framework.Execute([]string{"svc", "routes"}); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "/users") || !strings.Contains(out.String(), "/healthz") {
+		t.Fatalf("expected routes output to list registered routes, got:\n%s", out.String())
+	}
+
+	withIsolatedDatabaseDrivers(t)
+	RegisterDatabaseDriver(func(cfg DatabaseConfig, logger gormlogger.Interface) (*gorm.DB, error) {
+		return &gorm.DB{}, nil
+	}, DatabaseType("stub"))
+	out.Reset()
+	if err := framework.Execute([]string{"svc", "drivers"}); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if out.String() != "stub\n" {
+		t.Fatalf("unexpected drivers output: %q", out.String())
+	}
+}
@@ -7,8 +7,12 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
@@ -23,6 +27,7 @@ const (
 )
 
 // Unmarshal 解析配置到指定结构
+// 通过 JSON 转换，不支持 mapstructure 标签与时长字符串，推荐使用 AppConfigAs
 func (r AppConfig) Unmarshal(v any) error {
 	data, err := json.Marshal(r)
 	if err != nil {
@@ -32,89 +37,212 @@ func (r AppConfig) Unmarshal(v any) error {
 }
 
 type Config struct {
-	Log      LogConfig      `yaml:"log" mapstructure:"log"`           // 日志配置
-	Database DatabaseConfig `yaml:"database" mapstructure:"database"` // 数据库配置
-	Server   ServerConfig   `yaml:"server" mapstructure:"server"`     // Web 服务器配置
-	App      AppConfig      `yaml:"app" mapstructure:"app"`           // 应用程序个性化配置
+	Log       LogConfig                 `yaml:"log" mapstructure:"log"`             // 日志配置
+	Database  DatabaseConfig            `yaml:"database" mapstructure:"database"`   // 默认数据库配置
+	Databases map[string]DatabaseConfig `yaml:"databases" mapstructure:"databases"` // 具名数据库配置，名称 default 保留给默认数据库
+	Server    ServerConfig              `yaml:"server" mapstructure:"server"`       // Web 服务器配置
+	App       AppConfig                 `yaml:"app" mapstructure:"app"`             // 应用程序个性化配置
+	Source    SourceConfig              `yaml:"config" mapstructure:"config"`       // 配置文件自身的行为
+}
+
+type SourceConfig struct {
+	Watch bool `yaml:"watch" mapstructure:"watch" usage:"reload config when the config file changes"` // 配置文件变化时自动重新加载
 }
 
 type ServerConfig struct {
-	Addr        string   `yaml:"addr" mapstructure:"addr"`
-	IPExtractor string   `yaml:"ip_extractor" mapstructure:"ip_extractor"`
-	IPTrustList []string `yaml:"ip_trust_list" mapstructure:"ip_trust_list"` // 可信代理 IP/CIDR 列表，用于决定是否信任转发 IP 头
+	Addr        string          `yaml:"addr" mapstructure:"addr" usage:"HTTP listen address"`
+	IPExtractor string          `yaml:"ip_extractor" mapstructure:"ip_extractor" usage:"client IP source: direct, x-forwarded-for, x-real-ip or a header name"`
+	IPTrustList []string        `yaml:"ip_trust_list" mapstructure:"ip_trust_list" usage:"trusted proxy IPs or CIDRs"` // 可信代理 IP/CIDR 列表，用于决定是否信任转发 IP 头
+	Health      HealthConfig    `yaml:"health" mapstructure:"health"`                                                  // 健康检查配置
+	AccessLog   AccessLogConfig `yaml:"access_log" mapstructure:"access_log"`                                          // 访问日志配置
+	Admin       AdminConfig     `yaml:"admin" mapstructure:"admin"`                                                    // 管理接口配置
+}
+
+type AdminConfig struct {
+	Enabled       bool   `yaml:"enabled" mapstructure:"enabled" usage:"register admin endpoints on the HTTP server"`                            // 是否注册管理接口，接口本身不做认证
+	LogLevelsPath string `yaml:"log_levels_path" mapstructure:"log_levels_path" usage:"path of the endpoint that reads and changes log levels"` // 查看与修改日志级别的接口路径
+}
+
+type HealthConfig struct {
+	Enabled       bool          `yaml:"enabled" mapstructure:"enabled" usage:"register health check endpoints"`                                // 是否注册健康检查接口
+	LivenessPath  string        `yaml:"liveness_path" mapstructure:"liveness_path" usage:"liveness endpoint path, empty to disable"`           // 存活检查路径
+	ReadinessPath string        `yaml:"readiness_path" mapstructure:"readiness_path" usage:"readiness endpoint path, empty to disable"`        // 就绪检查路径
+	Timeout       time.Duration `yaml:"timeout" mapstructure:"timeout" usage:"readiness check timeout"`                                        // 就绪检查超时时间
+	DrainDelay    time.Duration `yaml:"drain_delay" mapstructure:"drain_delay" usage:"delay between marking not ready and shutting down HTTP"` // 标记为未就绪后等待多久再关闭 HTTP 服务
+}
+
+type AccessLogConfig struct {
+	Enabled       bool          `yaml:"enabled" mapstructure:"enabled" usage:"log one entry per HTTP request"`
+	Fields        []string      `yaml:"fields" mapstructure:"fields" usage:"fields to log: method, route, path, query, status, latency, bytes_in, bytes_out, ip, user_agent, referer, host, protocol, request_id, principal"`
+	SkipPaths     []string      `yaml:"skip_paths" mapstructure:"skip_paths" usage:"request paths or route templates that are not logged"`
+	SlowThreshold time.Duration `yaml:"slow_threshold" mapstructure:"slow_threshold" usage:"log requests slower than this at warn level, 0 to disable"` // 慢请求阈值，超过时以 warn 级别记录且不参与采样
+	Sampling      float64       `yaml:"sampling" mapstructure:"sampling" usage:"fraction of successful requests to log, from 0 to 1"`                   // 采样比例，错误与慢请求始终记录
+	Filename      string        `yaml:"filename" mapstructure:"filename" usage:"separate access log file, empty to use the application logger"`         // 单独的访问日志文件，为空时写入应用日志
+	MaxSize       int           `yaml:"max_size" mapstructure:"max_size" usage:"max access log file size in MB"`
+	MaxAge        int           `yaml:"max_age" mapstructure:"max_age" usage:"days to keep access log files"`
+	Compress      bool          `yaml:"compress" mapstructure:"compress" usage:"compress rotated access log files"`
 }
 
 type LogConfig struct {
-	Level    string `yaml:"level" mapstructure:"level"`       // debug, info, warn, error
-	Filename string `yaml:"filename" mapstructure:"filename"` // 日志文件路径
-	Encode   string `yaml:"encode" mapstructure:"encode"`     // console, json
-	Console  bool   `yaml:"console" mapstructure:"console"`   // 是否输出到控制台
-	MaxSize  int    `yaml:"max_size" mapstructure:"max_size"` // 日志文件最大大小(MB)
-	MaxAge   int    `yaml:"max_age" mapstructure:"max_age"`   // 日志保留天数
-	Compress bool   `yaml:"compress" mapstructure:"compress"` // 是否压缩日志
+	Level           string            `yaml:"level" mapstructure:"level" enum:"debug,info,warn,warning,error,fatal,panic" usage:"log level: debug, info, warn, error"`              // debug, info, warn, error
+	Levels          map[string]string `yaml:"levels" mapstructure:"levels" usage:"log levels by logger name, e.g. gorm: warn"`                                                      // 按日志器名称设置的级别，子日志器沿用父名称的级别
+	Filename        string            `yaml:"filename" mapstructure:"filename" usage:"log file path, empty to disable file output"`                                                 // 日志文件路径
+	Encode          string            `yaml:"encode" mapstructure:"encode" enum:"console,json" usage:"log encoding: console, json"`                                                 // console, json
+	Console         bool              `yaml:"console" mapstructure:"console" usage:"write logs to stdout"`                                                                          // 是否输出到控制台
+	MaxSize         int               `yaml:"max_size" mapstructure:"max_size" usage:"max log file size in MB"`                                                                     // 日志文件最大大小(MB)
+	MaxAge          int               `yaml:"max_age" mapstructure:"max_age" usage:"days to keep log files"`                                                                        // 日志保留天数
+	Compress        bool              `yaml:"compress" mapstructure:"compress" usage:"compress rotated log files"`                                                                  // 是否压缩日志
+	Rotate          string            `yaml:"rotate" mapstructure:"rotate" enum:"size,daily,hourly" usage:"log rotation: size, daily, hourly"`                                      // 滚动方式：size, daily, hourly
+	MaxBackups      int               `yaml:"max_backups" mapstructure:"max_backups" usage:"max rotated log files to keep, 0 to keep all"`                                          // 保留的滚动文件数量，0 表示不限制
+	FilenamePattern string            `yaml:"filename_pattern" mapstructure:"filename_pattern" usage:"log file name pattern for daily/hourly rotation, e.g. logs/app-%Y-%m-%d.log"` // 按时间滚动的文件名，支持 %Y %m %d %H %M
+
+	Sampling   LogSamplingConfig `yaml:"sampling" mapstructure:"sampling"`                                                                       // 按消息采样
+	RateLimit  int               `yaml:"rate_limit" mapstructure:"rate_limit" usage:"max entries per second for the same message, 0 to disable"` // 同一消息每秒最多写入的条数
+	RedactKeys []string          `yaml:"redact_keys" mapstructure:"redact_keys" usage:"field and JSON keys whose values are masked"`             // 字段名或字符串中 JSON 键包含这些关键字时隐藏其值
+}
+
+// LogSamplingConfig 每秒内同一消息先记录 initial 条，之后每 thereafter 条记录一条
+type LogSamplingConfig struct {
+	Initial    int `yaml:"initial" mapstructure:"initial" usage:"entries per second logged for the same message before sampling, 0 to disable"`
+	Thereafter int `yaml:"thereafter" mapstructure:"thereafter" usage:"after initial, log every Nth entry of the same message, 0 to drop the rest"`
 }
 
 type DatabaseConfig struct {
-	Enabled  bool         `yaml:"enabled" mapstructure:"enabled"`
-	Type     DatabaseType `yaml:"type" mapstructure:"type"`
-	URL      string       `yaml:"url" mapstructure:"url"`
+	Enabled  bool         `yaml:"enabled" mapstructure:"enabled" usage:"enable the database"`
+	Type     DatabaseType `yaml:"type" mapstructure:"type" usage:"database type: sqlite, mysql, postgres"`
+	URL      string       `yaml:"url" mapstructure:"url" usage:"database connection URL or DSN, overrides per-driver settings"`
 	Mysql    MysqlCfg     `yaml:"mysql" mapstructure:"mysql"`
 	Sqlite   SqliteConfig `yaml:"sqlite" mapstructure:"sqlite"`
 	Postgres PostgresCfg  `yaml:"postgres" mapstructure:"postgres"`
-	ShowSql  bool         `yaml:"show_sql" mapstructure:"show_sql"`
+	ShowSql  bool         `yaml:"show_sql" mapstructure:"show_sql" usage:"log SQL statements"`
+
+	Replicas      []DatabaseConfig `yaml:"replicas" mapstructure:"replicas"`                                                                                             // 只读副本，未指定 type 时沿用主库类型
+	ReplicaPolicy string           `yaml:"replica_policy" mapstructure:"replica_policy" enum:"random,round_robin" usage:"replica selection policy: random, round_robin"` // 副本选择策略：random, round_robin
+
+	AutoMigrate bool   `yaml:"auto_migrate" mapstructure:"auto_migrate" usage:"run AutoMigrate for registered models on startup"`                                   // 启动时对注册的模型执行 AutoMigrate
+	SchemaCheck string `yaml:"schema_check" mapstructure:"schema_check" enum:"off,warn,fail" usage:"check registered models against the database: off, warn, fail"` // 启动时检查注册的模型与数据库结构：off, warn, fail
 }
 
 type MysqlCfg struct {
-	Hostname string `yaml:"hostname" mapstructure:"hostname"`
-	Port     int    `yaml:"port" mapstructure:"port"`
-	Username string `yaml:"username" mapstructure:"username"`
-	Password string `yaml:"password" mapstructure:"password"`
-	Database string `yaml:"database" mapstructure:"database"`
+	Hostname string `yaml:"hostname" mapstructure:"hostname" usage:"MySQL host"`
+	Port     int    `yaml:"port" mapstructure:"port" usage:"MySQL port"`
+	Username string `yaml:"username" mapstructure:"username" usage:"MySQL user"`
+	Password string `yaml:"password" mapstructure:"password" usage:"MySQL password"`
+	Database string `yaml:"database" mapstructure:"database" usage:"MySQL database name"`
 }
 
 type PostgresCfg struct {
-	Hostname string `yaml:"hostname" mapstructure:"hostname"`
-	Port     int    `yaml:"port" mapstructure:"port"`
-	Username string `yaml:"username" mapstructure:"username"`
-	Password string `yaml:"password" mapstructure:"password"`
-	Database string `yaml:"database" mapstructure:"database"`
+	Hostname string `yaml:"hostname" mapstructure:"hostname" usage:"PostgreSQL host"`
+	Port     int    `yaml:"port" mapstructure:"port" usage:"PostgreSQL port"`
+	Username string `yaml:"username" mapstructure:"username" usage:"PostgreSQL user"`
+	Password string `yaml:"password" mapstructure:"password" usage:"PostgreSQL password"`
+	Database string `yaml:"database" mapstructure:"database" usage:"PostgreSQL database name"`
 }
 
 type SqliteConfig struct {
-	Path string `yaml:"path" mapstructure:"path"`
+	Path string `yaml:"path" mapstructure:"path" usage:"SQLite database file path"`
 }
 
 // ConfigManager 配置管理器
+// 记录每次加载的配置来源，Reload 时按原顺序重新加载
 type ConfigManager struct {
-	viper *viper.Viper
+	mu         sync.RWMutex
+	viper      *viper.Viper
+	sources    []configSource
+	state      *configState
+	configFile string
+	profile    string
+	keyFile    string
+	envPrefix  atomic.Pointer[string]
+	envTypes   []configEnvType                 // 需要绑定环境变量的应用配置段
+	bound      atomic.Pointer[map[string]bool] // envTypes 中已绑定的配置键，经 normalizeConfigKey 处理
+	flags      *pflag.FlagSet                  // 通过 BindFlags 绑定的命令行参数
+	secrets    atomic.Pointer[[]string]        // 通过引用解析或解密得到的敏感值，用于脱敏
+	dotEnv     []dotEnvSource                  // 通过 WithDotEnv 注册的 dotenv 文件
+	dotEnvVars map[string]string               // 由 dotenv 文件设置的环境变量及其来源文件
+}
+
+// configSource 一次配置加载，Reload 时在新的 viper 实例上重放
+type configSource func(v *viper.Viper, state *configState) error
+
+// configState 记录配置来源的加载结果
+type configState struct {
+	files   []string          // 已加载的配置文件，按加载顺序排列
+	origins map[string]string // 归一化的配置键 -> 最后提供该值的来源
+}
+
+func newConfigState() *configState {
+	return &configState{origins: make(map[string]string)}
 }
 
 // NewConfigManager 创建新的配置管理器
 func NewConfigManager() *ConfigManager {
+	cm := &ConfigManager{state: newConfigState()}
+	cm.viper = cm.newViper()
+	return cm
+}
+
+// configDefaults 框架配置的默认值
+var configDefaults = map[string]any{
+	"log.level":                        "info",
+	"log.filename":                     "",
+	"log.encode":                       "console",
+	"log.console":                      true,
+	"log.max_size":                     100,
+	"log.max_age":                      7,
+	"log.compress":                     true,
+	"log.rotate":                       "size",
+	"log.max_backups":                  0,
+	"log.filename_pattern":             "",
+	"log.sampling.initial":             0,
+	"log.sampling.thereafter":          0,
+	"log.rate_limit":                   0,
+	"log.redact_keys":                  defaultRedactKeys,
+	"database.enabled":                 true,
+	"database.type":                    "sqlite",
+	"database.show_sql":                false,
+	"database.auto_migrate":            false,
+	"database.schema_check":            SchemaCheckOff,
+	"server.addr":                      ":8080",
+	"server.health.enabled":            true,
+	"server.health.liveness_path":      "/healthz",
+	"server.health.readiness_path":     "/readyz",
+	"server.health.timeout":            3 * time.Second,
+	"server.health.drain_delay":        0,
+	"server.admin.enabled":             false,
+	"server.admin.log_levels_path":     "/admin/log-levels",
+	"server.access_log.enabled":        false,
+	"server.access_log.fields":         defaultAccessLogFields,
+	"server.access_log.skip_paths":     []string{"/healthz", "/readyz"},
+	"server.access_log.slow_threshold": time.Second,
+	"server.access_log.sampling":       1.0,
+	"server.access_log.max_size":       100,
+	"server.access_log.max_age":        7,
+	"server.access_log.compress":       true,
+	"config.watch":                     false,
+}
+
+// newViper 创建设置了默认值与环境变量绑定的 viper 实例
+func (cm *ConfigManager) newViper() *viper.Viper {
 	v := viper.New()
 	v.SetConfigType("yaml")
 
-	// 启用环境变量支持
+	// 启用环境变量支持，未显式绑定的键（例如 databases.<name>.url）由 AutomaticEnv 处理
 	v.AutomaticEnv()
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	cm.bindEnv(v)
+	if cm.flags != nil {
+		bindFlags(v, cm.flags, cm.envVars(cm.EnvPrefix()))
+	}
 
-	// 设置默认值
-	v.SetDefault("log.level", "info")
-	v.SetDefault("log.filename", "")
-	v.SetDefault("log.encode", "console")
-	v.SetDefault("log.console", true)
-	v.SetDefault("log.max_size", 100)
-	v.SetDefault("log.max_age", 7)
-	v.SetDefault("log.compress", true)
-	v.SetDefault("database.enabled", true)
-	v.SetDefault("database.type", "sqlite")
-	v.SetDefault("database.show_sql", false)
-	v.SetDefault("server.addr", ":8080")
-
-	return &ConfigManager{viper: v}
+	for key, value := range configDefaults {
+		v.SetDefault(key, value)
+	}
+	return v
 }
 
 // LoadFromFile 从文件加载配置
+// 设置了 profile 时随后合并 <base>.<profile>.<ext>，最后按文件名顺序合并同目录 conf.d 下的 YAML 文件
 func (cm *ConfigManager) LoadFromFile(configPath string) error {
 	if configPath == "" {
 		return fmt.Errorf("config path is empty")
@@ -124,42 +252,156 @@ func (cm *ConfigManager) LoadFromFile(configPath string) error {
 		return fmt.Errorf("config file does not exist: %s", configPath)
 	}
 
-	cm.viper.SetConfigFile(configPath)
-	return cm.viper.ReadInConfig()
+	if err := cm.load(func(v *viper.Viper, state *configState) error {
+		return cm.loadFiles(v, state, configPath)
+	}); err != nil {
+		return err
+	}
+
+	cm.mu.Lock()
+	cm.configFile = configPath
+	cm.mu.Unlock()
+	return nil
 }
 
 // LoadFromBytes 从字节数组加载配置
 func (cm *ConfigManager) LoadFromBytes(data []byte) error {
-	return cm.viper.ReadConfig(bytes.NewReader(data))
+	return cm.load(func(v *viper.Viper, state *configState) error {
+		if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+			return err
+		}
+		return state.recordBytes(data)
+	})
 }
 
 // LoadFromMap 从Map加载配置
 func (cm *ConfigManager) LoadFromMap(data map[string]interface{}) error {
-	return cm.viper.MergeConfigMap(data)
+	return cm.load(func(v *viper.Viper, state *configState) error {
+		if err := v.MergeConfigMap(data); err != nil {
+			return err
+		}
+		return state.recordMap(data)
+	})
+}
+
+func (cm *ConfigManager) load(source configSource) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if err := source(cm.viper, cm.state); err != nil {
+		return err
+	}
+	cm.sources = append(cm.sources, source)
+	return nil
+}
+
+// ConfigFile 返回通过 LoadFromFile 加载的配置文件路径，未从文件加载时为空
+func (cm *ConfigManager) ConfigFile() string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.configFile
+}
+
+// ConfigFiles 返回已加载的所有配置文件，包括 profile 文件与 conf.d 中的文件
+func (cm *ConfigManager) ConfigFiles() []string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return append([]string(nil), cm.state.files...)
+}
+
+// Reload 按原顺序重新加载所有配置来源
+// 新配置无法读取、解析或校验失败时返回错误，并保留当前配置
+func (cm *ConfigManager) Reload() error {
+	return cm.reload((*Config).Validate)
+}
+
+// reload 重新加载配置，validate 通过后才替换当前配置
+func (cm *ConfigManager) reload(validate func(*Config) error) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if err := cm.loadDotEnv(); err != nil {
+		return fmt.Errorf("failed to reload config: %w", err)
+	}
+	v := cm.newViper()
+	state := newConfigState()
+	for _, source := range cm.sources {
+		if err := source(v, state); err != nil {
+			return fmt.Errorf("failed to reload config: %w", err)
+		}
+	}
+	config, secrets, err := decodeConfig(v, cm.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to reload config: %w", err)
+	}
+	if err := validate(config); err != nil {
+		return fmt.Errorf("failed to reload config: %w", err)
+	}
+
+	cm.viper = v
+	cm.state = state
+	cm.setSecrets(secrets)
+	return nil
 }
 
-// GetConfig 获取配置
+// GetConfig 获取配置，解析失败时返回 nil
 func (cm *ConfigManager) GetConfig() *Config {
+	config, err := cm.Decode()
+	if err != nil {
+		return nil
+	}
+	return config
+}
+
+// Decode 解析当前配置
+// 字符串中的 ${env:NAME}、${env:NAME:-default} 与 ${file:path} 引用会被替换，ENC(...) 值会被解密，
+// 引用无法解析或无法解密时返回错误
+func (cm *ConfigManager) Decode() (*Config, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	config, secrets, err := decodeConfig(cm.viper, cm.keyFile)
+	if err != nil {
+		return nil, err
+	}
+	cm.setSecrets(secrets)
+	return config, nil
+}
+
+func decodeConfig(v *viper.Viper, keyFile string) (*Config, []string, error) {
+	settings, secrets, err := resolveConfigReferences(normalizeConfigSettings(v.AllSettings(), v.InConfig), keyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve config references: %w", err)
+	}
+
 	config := &Config{}
+	if err := decodeConfigValue(settings, config); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+	return config, secrets, nil
+}
+
+// decodeConfigValue 使用与 Config 相同的规则解析配置值，应用自定义配置也使用这套规则
+func decodeConfigValue(input any, result any) error {
 	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		DecodeHook: mapstructure.ComposeDecodeHookFunc(
 			mapstructure.StringToTimeDurationHookFunc(),
 			mapstructure.StringToSliceHookFunc(","),
 		),
 		MatchName:        matchConfigName,
-		Result:           config,
+		Result:           result,
 		TagName:          "mapstructure",
 		WeaklyTypedInput: true,
 	})
 	if err != nil {
-		return nil
-	}
-	if err := decoder.Decode(normalizeConfigSettings(cm.viper.AllSettings(), cm.viper.InConfig)); err != nil {
-		return nil
+		return err
 	}
-	return config
+	return decoder.Decode(input)
 }
 
+// namedConfigMaps 以用户自定义名称为键的配置项，名称本身不做归一化
+var namedConfigMaps = []string{"databases", "log.levels"}
+
 type configValue struct {
 	value any
 	rank  int
@@ -187,7 +429,11 @@ func normalizeConfigMap(settings map[string]any, parent string, inConfig func(st
 
 		value := settings[key]
 		if child, ok := value.(map[string]any); ok && !matchConfigName(path, "app") {
-			value = normalizeConfigMap(child, path, inConfig)
+			if isNamedConfigMap(path) {
+				value = normalizeNamedConfigMap(child, path, inConfig)
+			} else {
+				value = normalizeConfigMap(child, path, inConfig)
+			}
 		}
 
 		normalizedKey := normalizeConfigName(key)
@@ -204,6 +450,41 @@ func normalizeConfigMap(settings map[string]any, parent string, inConfig func(st
 	return result
 }
 
+func normalizeNamedConfigMap(settings map[string]any, parent string, inConfig func(string) bool) map[string]any {
+	result := make(map[string]any, len(settings))
+	for name, value := range settings {
+		if child, ok := value.(map[string]any); ok {
+			value = normalizeConfigMap(child, parent+"."+name, inConfig)
+		}
+		result[name] = value
+	}
+	return result
+}
+
+func isNamedConfigMap(path string) bool {
+	for _, named := range namedConfigMaps {
+		if matchConfigPath(path, named) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchConfigPath 按段比较配置路径，每段使用与字段相同的归一化规则
+func matchConfigPath(path, target string) bool {
+	pathParts := strings.Split(path, ".")
+	targetParts := strings.Split(target, ".")
+	if len(pathParts) != len(targetParts) {
+		return false
+	}
+	for i := range pathParts {
+		if !matchConfigName(pathParts[i], targetParts[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 func configKeyRank(key, path string, inConfig func(string) bool) int {
 	rank := 0
 	if inConfig(path) {
@@ -228,20 +509,28 @@ func normalizeConfigName(name string) string {
 
 // Get 获取配置值
 func (cm *ConfigManager) Get(key string) interface{} {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
 	return cm.viper.Get(key)
 }
 
 // GetString 获取字符串配置
 func (cm *ConfigManager) GetString(key string) string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
 	return cm.viper.GetString(key)
 }
 
 // GetInt 获取整数配置
 func (cm *ConfigManager) GetInt(key string) int {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
 	return cm.viper.GetInt(key)
 }
 
 // GetBool 获取布尔配置
 func (cm *ConfigManager) GetBool(key string) bool {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
 	return cm.viper.GetBool(key)
 }
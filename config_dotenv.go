@@ -0,0 +1,290 @@
+package orz
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// defaultDotEnvFile WithDotEnv 未指定文件时加载的文件
+const defaultDotEnvFile = ".env"
+
+// WithDotEnv 在加载配置之前读取 dotenv 文件，未指定时读取当前目录的 .env，文件不存在时跳过
+// 已存在的环境变量不会被覆盖；多个文件定义同一变量时，先列出的文件优先
+func WithDotEnv(paths ...string) Option {
+	return func(f *Framework) error {
+		f.app.configManager.addDotEnv(paths, false)
+		return nil
+	}
+}
+
+// WithDotEnvOverride 与 WithDotEnv 相同，但 dotenv 文件中的值覆盖已存在的环境变量，后列出的文件优先
+func WithDotEnvOverride(paths ...string) Option {
+	return func(f *Framework) error {
+		f.app.configManager.addDotEnv(paths, true)
+		return nil
+	}
+}
+
+// dotEnvSource 通过 WithDotEnv 注册的 dotenv 文件
+type dotEnvSource struct {
+	paths    []string
+	override bool
+}
+
+func (cm *ConfigManager) addDotEnv(paths []string, override bool) {
+	if len(paths) == 0 {
+		paths = []string{defaultDotEnvFile}
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.dotEnv = append(cm.dotEnv, dotEnvSource{paths: paths, override: override})
+}
+
+// LoadDotEnv 读取 dotenv 文件并设置环境变量，文件不存在时跳过
+// override 为 false 时不覆盖已存在的环境变量。Reload 时会重新读取这些文件
+func (cm *ConfigManager) LoadDotEnv(override bool, paths ...string) error {
+	cm.addDotEnv(paths, override)
+	return cm.applyDotEnv()
+}
+
+func (cm *ConfigManager) applyDotEnv() error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	return cm.loadDotEnv()
+}
+
+// loadDotEnv 依次读取所有注册的 dotenv 文件
+// 之前由 dotenv 设置的变量视为可以更新，使 Reload 能读取到 .env 的修改
+func (cm *ConfigManager) loadDotEnv() error {
+	previous := cm.dotEnvVars
+	loaded := make(map[string]string)
+
+	for _, source := range cm.dotEnv {
+		for _, path := range source.paths {
+			data, err := os.ReadFile(path)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read dotenv file %s: %w", path, err)
+			}
+
+			entries, err := parseDotEnv(string(data))
+			if err != nil {
+				return fmt.Errorf("failed to parse dotenv file %s: %w", path, err)
+			}
+			for _, entry := range entries {
+				if _, ok := loaded[entry.key]; ok && !source.override {
+					continue
+				}
+				if _, fromDotEnv := previous[entry.key]; !fromDotEnv && !source.override {
+					if _, exists := os.LookupEnv(entry.key); exists {
+						continue
+					}
+				}
+				if err := os.Setenv(entry.key, entry.value); err != nil {
+					return fmt.Errorf("failed to set %s from %s: %w", entry.key, path, err)
+				}
+				loaded[entry.key] = path
+			}
+		}
+	}
+
+	// 从 dotenv 文件中删除的变量同样从环境中移除
+	for key := range previous {
+		if _, ok := loaded[key]; !ok {
+			os.Unsetenv(key)
+		}
+	}
+	cm.dotEnvVars = loaded
+	return nil
+}
+
+// DotEnvFiles 返回注册的 dotenv 文件
+func (cm *ConfigManager) DotEnvFiles() []string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	var files []string
+	for _, source := range cm.dotEnv {
+		files = append(files, source.paths...)
+	}
+	return files
+}
+
+// dotEnvEntry dotenv 文件中的一个变量
+type dotEnvEntry struct {
+	key   string
+	value string
+}
+
+// parseDotEnv 解析 dotenv 格式：KEY=value，支持 export 前缀、# 注释、
+// 单引号（原样保留）与双引号（支持 \n、\t、\"、\\ 转义），引号内的值可以跨行
+func parseDotEnv(data string) ([]dotEnvEntry, error) {
+	var entries []dotEnvEntry
+	p := &dotEnvParser{data: strings.ReplaceAll(data, "\r\n", "\n"), line: 1}
+
+	for {
+		p.skipBlankLines()
+		if p.done() {
+			return entries, nil
+		}
+
+		line := p.line
+		entry, err := p.parseEntry()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+}
+
+type dotEnvParser struct {
+	data string
+	pos  int
+	line int
+}
+
+func (p *dotEnvParser) done() bool {
+	return p.pos >= len(p.data)
+}
+
+// skipBlankLines 跳过空行与注释行
+func (p *dotEnvParser) skipBlankLines() {
+	for !p.done() {
+		rest := p.data[p.pos:]
+		end := strings.IndexByte(rest, '\n')
+		if end < 0 {
+			end = len(rest)
+		}
+		line := strings.TrimSpace(rest[:end])
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return
+		}
+		p.advance(end + 1)
+	}
+}
+
+func (p *dotEnvParser) advance(n int) {
+	if p.pos+n > len(p.data) {
+		n = len(p.data) - p.pos
+	}
+	p.line += strings.Count(p.data[p.pos:p.pos+n], "\n")
+	p.pos += n
+}
+
+// restOfLine 返回当前位置到行尾的内容并移动到下一行
+func (p *dotEnvParser) restOfLine() string {
+	rest := p.data[p.pos:]
+	end := strings.IndexByte(rest, '\n')
+	if end < 0 {
+		p.advance(len(rest))
+		return rest
+	}
+	p.advance(end + 1)
+	return rest[:end]
+}
+
+func (p *dotEnvParser) parseEntry() (dotEnvEntry, error) {
+	rest := p.data[p.pos:]
+	eq := strings.IndexByte(rest, '=')
+	newline := strings.IndexByte(rest, '\n')
+	if eq < 0 || (newline >= 0 && newline < eq) {
+		return dotEnvEntry{}, fmt.Errorf("expected KEY=value, got %q", strings.TrimSpace(p.restOfLine()))
+	}
+
+	key := strings.TrimSpace(rest[:eq])
+	if exported, ok := strings.CutPrefix(key, "export"); ok && exported != "" && (exported[0] == ' ' || exported[0] == '\t') {
+		key = strings.TrimSpace(exported)
+	}
+	if !validDotEnvKey(key) {
+		return dotEnvEntry{}, fmt.Errorf("invalid variable name %q", key)
+	}
+	p.advance(eq + 1)
+
+	// 跳过等号后的空白
+	for !p.done() && (p.data[p.pos] == ' ' || p.data[p.pos] == '\t') {
+		p.advance(1)
+	}
+
+	var value string
+	var err error
+	switch {
+	case !p.done() && p.data[p.pos] == '"':
+		value, err = p.quoted('"')
+	case !p.done() && p.data[p.pos] == '\'':
+		value, err = p.quoted('\'')
+	default:
+		value = p.restOfLine()
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = value[:i]
+		} else if i := strings.Index(value, "\t#"); i >= 0 {
+			value = value[:i]
+		}
+		value = strings.TrimSpace(value)
+	}
+	if err != nil {
+		return dotEnvEntry{}, err
+	}
+	return dotEnvEntry{key: key, value: value}, nil
+}
+
+// quoted 解析引号中的值，双引号支持转义，引号后只允许空白或注释
+func (p *dotEnvParser) quoted(quote byte) (string, error) {
+	start := p.line
+	p.advance(1)
+
+	var b strings.Builder
+	for {
+		if p.done() {
+			return "", fmt.Errorf("unterminated quoted value starting at line %d", start)
+		}
+		c := p.data[p.pos]
+		switch {
+		case c == quote:
+			p.advance(1)
+			trailing := strings.TrimSpace(p.restOfLine())
+			if trailing != "" && !strings.HasPrefix(trailing, "#") {
+				return "", fmt.Errorf("unexpected %q after quoted value", trailing)
+			}
+			return b.String(), nil
+		case c == '\\' && quote == '"' && p.pos+1 < len(p.data):
+			switch next := p.data[p.pos+1]; next {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\', '$':
+				b.WriteByte(next)
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(next)
+			}
+			p.advance(2)
+		default:
+			b.WriteByte(c)
+			p.advance(1)
+		}
+	}
+}
+
+func validDotEnvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		switch {
+		case r == '_' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z':
+		case i > 0 && (r >= '0' && r <= '9' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
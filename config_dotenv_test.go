@@ -0,0 +1,148 @@
+package orz
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// unsetEnv 在测试期间移除环境变量，测试结束后恢复原值
+func unsetEnv(t *testing.T, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+}
+
+func TestParseDotEnv(t *testing.T) {
+	entries, err := parseDotEnv(`# comment
+PLAIN=value
+export EXPORTED = spaced value  # trailing comment
+EMPTY=
+HASH=a#b
+SINGLE='literal \n $HOME'
+DOUBLE="tab\there \"quoted\""
+MULTI="line one
+line two"
+CERT='-----BEGIN-----
+abc
+-----END-----'
+`)
+	if err != nil {
+		t.Fatalf("parseDotEnv returned error: %v", err)
+	}
+
+	expected := []dotEnvEntry{
+		{"PLAIN", "value"},
+		{"EXPORTED", "spaced value"},
+		{"EMPTY", ""},
+		{"HASH", "a#b"},
+		{"SINGLE", `literal \n $HOME`},
+		{"DOUBLE", "tab\there \"quoted\""},
+		{"MULTI", "line one\nline two"},
+		{"CERT", "-----BEGIN-----\nabc\n-----END-----"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %v", len(expected), entries)
+	}
+	for i, entry := range entries {
+		if entry != expected[i] {
+			t.Fatalf("entry %d: expected %q, got %q", i, expected[i], entry)
+		}
+	}
+}
+
+func TestParseDotEnvErrors(t *testing.T) {
+	for input, expected := range map[string]string{
+		"A=1\nnot a pair\n":    "line 2: expected KEY=value",
+		"A=1\n\nB=\"open\nC=2": "line 3: unterminated quoted value",
+		"1BAD=x":               "line 1: invalid variable name \"1BAD\"",
+		"A='x' trailing":       "line 1: unexpected \"trailing\" after quoted value",
+	} {
+		if _, err := parseDotEnv(input); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("parseDotEnv(%q): expected error containing %q, got %v", input, expected, err)
+		}
+	}
+}
+
+func TestWithDotEnvKeepsRealEnv(t *testing.T) {
+	dir := t.TempDir()
+	local := filepath.Join(dir, ".env.local")
+	shared := filepath.Join(dir, ".env")
+	writeConfigFile(t, local, "LOG_LEVEL=warn\n")
+	writeConfigFile(t, shared, "LOG_LEVEL=error\nSERVER_ADDR=:9100\nexport DATABASE_TYPE=sqlite\n")
+	writeConfigFile(t, filepath.Join(dir, "config.yaml"), "log:\n  level: info\n")
+	unsetEnv(t, "LOG_LEVEL", "SERVER_ADDR")
+	t.Setenv("DATABASE_TYPE", "sqlite")
+
+	framework, err := NewFramework(
+		WithLogger(zap.NewNop()),
+		WithDotEnv(local, shared, filepath.Join(dir, "missing.env")),
+		WithConfig(filepath.Join(dir, "config.yaml")),
+	)
+	if err != nil {
+		t.Fatalf("NewFramework returned error: %v", err)
+	}
+
+	config := framework.app.GetConfig()
+	if config.Log.Level != "warn" || config.Server.Addr != ":9100" {
+		t.Fatalf("expected dotenv values with earlier files first, got level=%q addr=%q", config.Log.Level, config.Server.Addr)
+	}
+	cm := framework.app.configManager
+	if origin := cm.Origin("log.level"); origin != "env:LOG_LEVEL ("+local+")" {
+		t.Fatalf("unexpected origin %q", origin)
+	}
+	if origin := cm.Origin("database.type"); origin != "env:DATABASE_TYPE" {
+		t.Fatalf("expected real env var to keep its origin, got %q", origin)
+	}
+
+	writeConfigFile(t, local, "SERVER_ADDR=:9200\n")
+	if err := cm.Reload(); err != nil {
+		t.Fatalf("Reload returned error: %v", err)
+	}
+	config = framework.app.GetConfig()
+	if config.Server.Addr != ":9200" || config.Log.Level != "error" {
+		t.Fatalf("expected reload to pick up dotenv changes, got level=%q addr=%q", config.Log.Level, config.Server.Addr)
+	}
+}
+
+func TestWithDotEnvOverrideAndProfile(t *testing.T) {
+	dir := t.TempDir()
+	dotenv := filepath.Join(dir, ".env")
+	writeConfigFile(t, dotenv, "ORZ_PROFILE=prod\nLOG_LEVEL=debug\n")
+	writeConfigFile(t, filepath.Join(dir, "config.yaml"), "server:\n  addr: \":8081\"\n")
+	writeConfigFile(t, filepath.Join(dir, "config.prod.yaml"), "server:\n  addr: \":8443\"\n")
+	unsetEnv(t, ProfileEnv)
+	t.Setenv("LOG_LEVEL", "info")
+
+	framework, err := NewFramework(
+		WithLogger(zap.NewNop()),
+		WithDotEnvOverride(dotenv),
+		WithConfig(filepath.Join(dir, "config.yaml")),
+	)
+	if err != nil {
+		t.Fatalf("NewFramework returned error: %v", err)
+	}
+
+	config := framework.app.GetConfig()
+	if config.Server.Addr != ":8443" {
+		t.Fatalf("expected profile from dotenv to apply, got %q", config.Server.Addr)
+	}
+	if config.Log.Level != "debug" {
+		t.Fatalf("expected dotenv to override env, got %q", config.Log.Level)
+	}
+}
+
+func TestWithDotEnvReportsParseErrors(t *testing.T) {
+	dotenv := filepath.Join(t.TempDir(), ".env")
+	writeConfigFile(t, dotenv, "OK=1\nBROKEN\n")
+
+	_, err := NewFramework(WithLogger(zap.NewNop()), WithDotEnv(dotenv))
+	if err == nil || !strings.Contains(err.Error(), dotenv+": line 2") {
+		t.Fatalf("expected parse error with file and line, got %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+package orz
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// ConfigKeyEnv 配置加密密钥，格式为 base64 编码的 16/24/32 字节 AES 密钥，
+	// 多个密钥以逗号分隔并带上密钥 ID，例如 "2024:<base64>,2023:<base64>"，第一个密钥用于加密
+	ConfigKeyEnv = "ORZ_CONFIG_KEY"
+
+	// ConfigKeyFileEnv 配置加密密钥文件路径，文件每行一个密钥，格式与 ORZ_CONFIG_KEY 相同
+	ConfigKeyFileEnv = "ORZ_CONFIG_KEY_FILE"
+
+	encryptedPrefix = "ENC("
+	encryptedSuffix = ")"
+)
+
+// WithConfigKeyFile 指定配置加密密钥文件，优先于 ORZ_CONFIG_KEY 与 ORZ_CONFIG_KEY_FILE
+func WithConfigKeyFile(path string) Option {
+	return func(f *Framework) error {
+		f.app.configManager.SetKeyFile(path)
+		return nil
+	}
+}
+
+// SetKeyFile 设置配置加密密钥文件
+func (cm *ConfigManager) SetKeyFile(path string) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.keyFile = path
+}
+
+// configKey 一个配置加密密钥，id 为空表示未指定密钥 ID
+type configKey struct {
+	id  string
+	key []byte
+}
+
+// configKeyring 配置加密密钥，第一个密钥用于加密，解密时按密钥 ID 查找
+type configKeyring []configKey
+
+// EncryptValue 使用当前第一个密钥加密配置值，返回可直接写入配置文件的 ENC(...) 字符串
+func (cm *ConfigManager) EncryptValue(plaintext string) (string, error) {
+	keyring, err := cm.keyring()
+	if err != nil {
+		return "", err
+	}
+	return keyring.encrypt(plaintext)
+}
+
+// DecryptValue 解密 ENC(...) 形式的配置值
+func (cm *ConfigManager) DecryptValue(value string) (string, error) {
+	keyring, err := cm.keyring()
+	if err != nil {
+		return "", err
+	}
+	return keyring.decrypt(value)
+}
+
+func (cm *ConfigManager) keyring() (configKeyring, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return loadKeyring(cm.keyFile)
+}
+
+// loadKeyring 按 keyFile、ORZ_CONFIG_KEY、ORZ_CONFIG_KEY_FILE 的顺序读取密钥
+func loadKeyring(keyFile string) (configKeyring, error) {
+	if keyFile == "" {
+		if keys := os.Getenv(ConfigKeyEnv); keys != "" {
+			return parseKeyring(keys, ConfigKeyEnv)
+		}
+		keyFile = os.Getenv(ConfigKeyFileEnv)
+	}
+	if keyFile == "" {
+		return nil, fmt.Errorf("no config key, set %s or %s", ConfigKeyEnv, ConfigKeyFileEnv)
+	}
+
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config key file: %w", err)
+	}
+	return parseKeyring(string(data), keyFile)
+}
+
+// parseKeyring 解析以逗号或换行分隔的 [id:]base64 密钥列表，# 开头的行为注释
+func parseKeyring(data, source string) (configKeyring, error) {
+	var keyring configKeyring
+	seen := make(map[string]bool)
+	for _, line := range strings.FieldsFunc(data, func(r rune) bool { return r == ',' || r == '\n' }) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		id, encoded, ok := strings.Cut(line, ":")
+		if !ok {
+			id, encoded = "", line
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config key %q in %s: %w", id, source, err)
+		}
+		if _, err := aes.NewCipher(key); err != nil {
+			return nil, fmt.Errorf("invalid config key %q in %s: %w", id, source, err)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("duplicate config key %q in %s", id, source)
+		}
+		seen[id] = true
+		keyring = append(keyring, configKey{id: id, key: key})
+	}
+	if len(keyring) == 0 {
+		return nil, fmt.Errorf("no config key in %s", source)
+	}
+	return keyring, nil
+}
+
+// encrypt 使用第一个密钥加密，返回 ENC([id:]base64) 形式的配置值
+func (k configKeyring) encrypt(plaintext string) (string, error) {
+	key := k[0]
+	aead, err := newConfigAEAD(key.key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(key.id))
+
+	payload := base64.StdEncoding.EncodeToString(sealed)
+	if key.id != "" {
+		payload = key.id + ":" + payload
+	}
+	return encryptedPrefix + payload + encryptedSuffix, nil
+}
+
+// decrypt 解密 ENC([id:]base64) 形式的配置值
+func (k configKeyring) decrypt(value string) (string, error) {
+	payload, ok := encryptedPayload(value)
+	if !ok {
+		return "", fmt.Errorf("value is not in ENC(...) format")
+	}
+	id, encoded, ok := strings.Cut(payload, ":")
+	if !ok {
+		id, encoded = "", payload
+	}
+
+	var key []byte
+	for _, candidate := range k {
+		if candidate.id == id {
+			key = candidate.key
+			break
+		}
+	}
+	if key == nil {
+		return "", fmt.Errorf("no config key with id %q", id)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %w", err)
+	}
+	aead, err := newConfigAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("invalid encrypted value: too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(id))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value with key %q: %w", id, err)
+	}
+	return string(plaintext), nil
+}
+
+func newConfigAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptedPayload 返回 ENC(...) 括号内的内容
+func encryptedPayload(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, encryptedPrefix) || !strings.HasSuffix(value, encryptedSuffix) {
+		return "", false
+	}
+	return value[len(encryptedPrefix) : len(value)-len(encryptedSuffix)], true
+}
+
+// decryptValue 解密配置中的 ENC(...) 值，密钥仅在遇到加密值时读取一次
+func (r *referenceResolver) decryptValue(value string) (string, error) {
+	if r.keyring == nil && r.keyringErr == nil {
+		r.keyring, r.keyringErr = loadKeyring(r.keyFile)
+	}
+	if r.keyringErr != nil {
+		return "", r.keyringErr
+	}
+	return r.keyring.decrypt(value)
+}
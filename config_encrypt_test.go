@@ -0,0 +1,110 @@
+package orz
+
+import (
+	"bytes"
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testConfigKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+}
+
+func TestKeyringEncryptDecryptWithRotation(t *testing.T) {
+	oldKeyring, err := parseKeyring("2023:"+testConfigKey(1), "test")
+	if err != nil {
+		t.Fatalf("parseKeyring returned error: %v", err)
+	}
+	encrypted, err := oldKeyring.encrypt("hunter2")
+	if err != nil {
+		t.Fatalf("encrypt returned error: %v", err)
+	}
+	if !strings.HasPrefix(encrypted, "ENC(2023:") {
+		t.Fatalf("expected key id in encrypted value, got %q", encrypted)
+	}
+
+	rotated, err := parseKeyring("2024:"+testConfigKey(2)+",\n2023:"+testConfigKey(1), "test")
+	if err != nil {
+		t.Fatalf("parseKeyring returned error: %v", err)
+	}
+	if decrypted, err := rotated.decrypt(encrypted); err != nil || decrypted != "hunter2" {
+		t.Fatalf("decrypt = %q, %v, want hunter2", decrypted, err)
+	}
+	reencrypted, err := rotated.encrypt("hunter2")
+	if err != nil || !strings.HasPrefix(reencrypted, "ENC(2024:") {
+		t.Fatalf("expected new values to use the first key, got %q, %v", reencrypted, err)
+	}
+
+	tampered := strings.Replace(encrypted, "ENC(2023:", "ENC(2024:", 1)
+	if _, err := rotated.decrypt(tampered); err == nil {
+		t.Fatalf("expected value sealed with another key id to fail")
+	}
+}
+
+func TestParseKeyringRejectsInvalidKeys(t *testing.T) {
+	for _, keys := range []string{"", "short:" + base64.StdEncoding.EncodeToString([]byte("short")), "a:" + testConfigKey(1) + ",a:" + testConfigKey(2), "not base64"} {
+		if _, err := parseKeyring(keys, "test"); err == nil {
+			t.Fatalf("expected parseKeyring(%q) to fail", keys)
+		}
+	}
+}
+
+func TestDecodeDecryptsEncryptedValues(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "config.key")
+	writeConfigFile(t, keyFile, "# current key\nprod:"+testConfigKey(3)+"\n")
+
+	cm := NewConfigManager()
+	cm.SetKeyFile(keyFile)
+	encrypted, err := cm.EncryptValue("s3cr3t-password")
+	if err != nil {
+		t.Fatalf("EncryptValue returned error: %v", err)
+	}
+	if err := cm.LoadFromMap(map[string]interface{}{
+		"database": map[string]interface{}{
+			"type":  "mysql",
+			"mysql": map[string]interface{}{"password": encrypted},
+		},
+	}); err != nil {
+		t.Fatalf("LoadFromMap returned error: %v", err)
+	}
+
+	config, err := cm.Decode()
+	if err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+	if config.Database.Mysql.Password != "s3cr3t-password" {
+		t.Fatalf("expected decrypted password, got %q", config.Database.Mysql.Password)
+	}
+	if redacted := cm.RedactSecrets("login with s3cr3t-password"); redacted != "login with ******" {
+		t.Fatalf("expected decrypted value to be redacted, got %q", redacted)
+	}
+
+	cm.SetKeyFile(filepath.Join(t.TempDir(), "missing.key"))
+	if _, err := cm.Decode(); err == nil || !strings.Contains(err.Error(), "database.mysql.password: failed to read config key file") {
+		t.Fatalf("expected missing key error, got %v", err)
+	}
+}
+
+func TestExecuteConfigEncryptDecrypt(t *testing.T) {
+	t.Setenv(ConfigKeyEnv, testConfigKey(4))
+	framework, out := newCommandFramework(t)
+
+	framework.stdin = strings.NewReader("from-stdin\n")
+	if err := framework.Execute([]string{"svc", "config", "encrypt"}); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	encrypted := strings.TrimSpace(out.String())
+	if !strings.HasPrefix(encrypted, "ENC(") || strings.Contains(encrypted, "from-stdin") {
+		t.Fatalf("unexpected encrypted value %q", encrypted)
+	}
+
+	out.Reset()
+	if err := framework.Execute([]string{"svc", "config", "decrypt", encrypted}); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if got := strings.TrimSpace(out.String()); got != "from-stdin" {
+		t.Fatalf("expected decrypted value, got %q", got)
+	}
+}
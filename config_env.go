@@ -0,0 +1,219 @@
+package orz
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// EnvVar 可以覆盖配置项的环境变量
+type EnvVar struct {
+	Name    string // 环境变量名，例如 MYSVC_SERVER_ADDR
+	Key     string // 配置键，例如 server.addr
+	Type    string // 值类型，例如 string、int、duration、[]string
+	Default string // 默认值，没有默认值时为空
+	Usage   string // 字段说明，取自 usage 标签
+}
+
+// configEnvType 绑定环境变量的应用配置段
+type configEnvType struct {
+	path string
+	typ  reflect.Type
+}
+
+// WithEnvPrefix 设置环境变量前缀，例如 WithEnvPrefix("MYSVC") 后 server.addr 只读取 MYSVC_SERVER_ADDR
+func WithEnvPrefix(prefix string) Option {
+	return func(f *Framework) error {
+		return f.app.configManager.SetEnvPrefix(prefix)
+	}
+}
+
+// SetEnvPrefix 设置环境变量前缀，前缀会被转为大写，末尾的 _ 可以省略
+// viper 的环境变量绑定无法撤销，已加载的配置来源会在新的 viper 实例上重新加载
+func (cm *ConfigManager) SetEnvPrefix(prefix string) error {
+	prefix = strings.ToUpper(strings.TrimSuffix(prefix, "_"))
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	previous := cm.envPrefix.Load()
+	cm.envPrefix.Store(&prefix)
+
+	v := cm.newViper()
+	state := newConfigState()
+	for _, source := range cm.sources {
+		if err := source(v, state); err != nil {
+			cm.envPrefix.Store(previous)
+			return fmt.Errorf("failed to reload config with env prefix %q: %w", prefix, err)
+		}
+	}
+	cm.viper = v
+	cm.state = state
+	return nil
+}
+
+// EnvPrefix 返回环境变量前缀，未设置时为空
+func (cm *ConfigManager) EnvPrefix() string {
+	if prefix := cm.envPrefix.Load(); prefix != nil {
+		return *prefix
+	}
+	return ""
+}
+
+// BindEnv 将 target 类型的每个字段绑定到 <path>.<field> 对应的环境变量，
+// 使配置文件中不存在的键也能通过环境变量设置，并出现在 EnvVars 列表中
+func (cm *ConfigManager) BindEnv(path string, target any) {
+	t := reflect.TypeOf(target)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || !isConfigSection(t) {
+		panic(fmt.Sprintf("orz: BindEnv requires a struct target, got %T", target))
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	envType := configEnvType{path: path, typ: t}
+	cm.envTypes = append(cm.envTypes, envType)
+	envVars := collectEnvVars(envType.typ, envType.path, cm.EnvPrefix())
+	bindEnvVars(cm.viper, envVars)
+
+	bound := maps.Clone(cm.boundKeys())
+	if bound == nil {
+		bound = make(map[string]bool)
+	}
+	for _, envVar := range envVars {
+		bound[normalizeConfigKey(envVar.Key)] = true
+	}
+	cm.bound.Store(&bound)
+	if cm.flags != nil {
+		bindFlags(cm.viper, cm.flags, envVars)
+	}
+}
+
+// bindEnv 将框架配置与已注册应用配置段的所有字段显式绑定到带前缀的环境变量
+func (cm *ConfigManager) bindEnv(v *viper.Viper) {
+	prefix := cm.EnvPrefix()
+	v.SetEnvPrefix(prefix)
+	bindEnvVars(v, cm.envVars(prefix))
+}
+
+func bindEnvVars(v *viper.Viper, envVars []EnvVar) {
+	for _, envVar := range envVars {
+		// 参数非空时 BindEnv 不会返回错误
+		_ = v.BindEnv(envVar.Key, envVar.Name)
+	}
+}
+
+// boundKeys 返回通过 BindEnv 绑定的配置键，其环境变量与命令行参数已由 viper 合并到配置中
+// 不加锁读取，可以在 reload 的校验回调中调用
+func (cm *ConfigManager) boundKeys() map[string]bool {
+	if bound := cm.bound.Load(); bound != nil {
+		return *bound
+	}
+	return nil
+}
+
+// EnvVars 返回所有显式支持的环境变量，按配置键排序
+func (cm *ConfigManager) EnvVars() []EnvVar {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.envVars(cm.EnvPrefix())
+}
+
+func (cm *ConfigManager) envVars(prefix string) []EnvVar {
+	envVars := collectEnvVars(reflect.TypeFor[Config](), "", prefix)
+	for _, envType := range cm.envTypes {
+		envVars = append(envVars, collectEnvVars(envType.typ, envType.path, prefix)...)
+	}
+	sort.SliceStable(envVars, func(i, j int) bool {
+		return envVars[i].Key < envVars[j].Key
+	})
+	return envVars
+}
+
+// collectEnvVars 递归收集结构体中可由单个环境变量表示的字段，map 与结构体切片不参与绑定
+func collectEnvVars(t reflect.Type, path, prefix string) []EnvVar {
+	var envVars []EnvVar
+	for _, f := range configFields(t) {
+		fieldType := f.field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		key := path
+		if f.key != "" {
+			key = joinConfigKey(path, f.key)
+		}
+		if isConfigSection(fieldType) {
+			envVars = append(envVars, collectEnvVars(fieldType, key, prefix)...)
+			continue
+		}
+
+		typeName, ok := configTypeName(fieldType)
+		if !ok {
+			continue
+		}
+		envVar := EnvVar{Name: configEnvName(prefix, key), Key: key, Type: typeName, Usage: f.field.Tag.Get("usage")}
+		if value, ok := configDefaults[key]; ok {
+			envVar.Default = formatConfigDefault(value)
+		} else if value, ok := f.field.Tag.Lookup("default"); ok {
+			envVar.Default = value
+		}
+		envVars = append(envVars, envVar)
+	}
+	return envVars
+}
+
+// formatConfigDefault 格式化默认值，切片以逗号分隔，与环境变量及命令行参数的写法一致
+func formatConfigDefault(value any) string {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice {
+		return fmt.Sprint(value)
+	}
+	items := make([]string, v.Len())
+	for i := range items {
+		items[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return strings.Join(items, ",")
+}
+
+// configTypeName 返回可由环境变量表示的字段类型名称
+func configTypeName(t reflect.Type) (string, bool) {
+	if t == reflect.TypeFor[time.Duration]() {
+		return "duration", true
+	}
+	switch t.Kind() {
+	case reflect.Map, reflect.Struct, reflect.Interface, reflect.Func, reflect.Chan:
+		return "", false
+	case reflect.Slice, reflect.Array:
+		elem, ok := configTypeName(t.Elem())
+		if !ok || t.Elem().Kind() == reflect.Slice {
+			return "", false
+		}
+		return "[]" + elem, true
+	default:
+		return t.Kind().String(), true
+	}
+}
+
+func joinConfigKey(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// configEnvName 返回配置路径对应的环境变量名，规则与 viper 的 AutomaticEnv 一致
+func configEnvName(prefix, path string) string {
+	name := strings.ToUpper(strings.ReplaceAll(path, ".", "_"))
+	if prefix != "" {
+		name = prefix + "_" + name
+	}
+	return name
+}
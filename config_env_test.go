@@ -0,0 +1,103 @@
+package orz
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type envPaymentConfig struct {
+	Provider string        `mapstructure:"provider" default:"stripe"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+	Retry    struct {
+		Max int `mapstructure:"max"`
+	} `mapstructure:"retry"`
+}
+
+func TestEnvPrefixIgnoresUnprefixedVariables(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("MYSVC_SERVER_ADDR", ":9090")
+	t.Setenv("MYSVC_DATABASE_MYSQL_PASSWORD", "from-env")
+
+	cm := NewConfigManager()
+	if err := cm.LoadFromMap(map[string]interface{}{
+		"database": map[string]interface{}{"type": "sqlite"},
+	}); err != nil {
+		t.Fatalf("LoadFromMap returned error: %v", err)
+	}
+	if err := cm.SetEnvPrefix("mysvc_"); err != nil {
+		t.Fatalf("SetEnvPrefix returned error: %v", err)
+	}
+
+	config := cm.GetConfig()
+	if config.Log.Level != "info" {
+		t.Fatalf("expected unprefixed LOG_LEVEL to be ignored, got %q", config.Log.Level)
+	}
+	if config.Server.Addr != ":9090" {
+		t.Fatalf("expected prefixed env to override server.addr, got %q", config.Server.Addr)
+	}
+	if config.Database.Mysql.Password != "from-env" {
+		t.Fatalf("expected key absent from config to be read from env, got %q", config.Database.Mysql.Password)
+	}
+	if origin := cm.Origin("server.addr"); origin != "env:MYSVC_SERVER_ADDR" {
+		t.Fatalf("unexpected origin %q", origin)
+	}
+
+	if err := cm.Reload(); err != nil {
+		t.Fatalf("Reload returned error: %v", err)
+	}
+	if config := cm.GetConfig(); config.Server.Addr != ":9090" || config.Log.Level != "info" {
+		t.Fatalf("expected env prefix to survive reload, got addr=%q level=%q", config.Server.Addr, config.Log.Level)
+	}
+}
+
+func TestBindAppConfigReadsEnvWithPrefix(t *testing.T) {
+	t.Setenv("MYSVC_APP_PAYMENTS_RETRY_MAX", "5")
+	t.Setenv("MYSVC_APP_PAYMENTS_TIMEOUT", "2s")
+
+	app := NewApp()
+	if err := app.configManager.SetEnvPrefix("MYSVC"); err != nil {
+		t.Fatalf("SetEnvPrefix returned error: %v", err)
+	}
+	app.BindAppConfig("payments", &envPaymentConfig{})
+	if err := app.LoadConfigFromMap(map[string]interface{}{"app": map[string]interface{}{"name": "demo"}}); err != nil {
+		t.Fatalf("LoadConfigFromMap returned error: %v", err)
+	}
+
+	payments, ok := app.GetConfig().App["payments"].(map[string]any)
+	if !ok || payments["timeout"] != "2s" {
+		t.Fatalf("expected bound app keys in config, got %v", app.GetConfig().App)
+	}
+
+	config, err := AppConfigAs[envPaymentConfig](app, "payments")
+	if err != nil {
+		t.Fatalf("AppConfigAs returned error: %v", err)
+	}
+	if config.Provider != "stripe" || config.Timeout != 2*time.Second || config.Retry.Max != 5 {
+		t.Fatalf("unexpected app config: %+v", config)
+	}
+}
+
+func TestExecuteConfigEnvListsVariables(t *testing.T) {
+	framework, out := newCommandFramework(t, WithEnvPrefix("MYSVC"), WithAppConfig("payments", &envPaymentConfig{}))
+
+	if err := framework.Execute([]string{"svc", "config", "env"}); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	printed := out.String()
+	for _, expected := range []string{
+		"MYSVC_SERVER_ADDR", "server.addr", ":8080",
+		"MYSVC_SERVER_HEALTH_TIMEOUT", "duration",
+		"MYSVC_SERVER_IP_TRUST_LIST", "[]string",
+		"password,token,authorization,secret",
+		"MYSVC_APP_PAYMENTS_PROVIDER", "stripe",
+		"MYSVC_APP_PAYMENTS_RETRY_MAX",
+	} {
+		if !strings.Contains(printed, expected) {
+			t.Fatalf("expected output to contain %q, got:\n%s", expected, printed)
+		}
+	}
+	if strings.Contains(printed, "REPLICAS") || strings.Contains(printed, "MYSVC_DATABASES") {
+		t.Fatalf("expected maps and struct slices to be skipped, got:\n%s", printed)
+	}
+}
@@ -0,0 +1,151 @@
+package orz
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// WithFlags 解析命令行中的配置参数，args 通常为 os.Args
+// 每个配置项对应一个 --<key> 参数，例如 --server.addr=:9000 --log.level=debug，
+// --config 替换 WithConfig 指定的配置文件。配置参数需要写在子命令之前，例如 ./app --log.level=debug migrate up。
+// 优先级为：命令行参数 > 环境变量 > 配置文件 > 默认值
+func WithFlags(args []string) Option {
+	return func(f *Framework) error {
+		f.flagArgs = append([]string{}, args...)
+		return nil
+	}
+}
+
+// parseFlags 在加载配置之前解析 WithFlags 传入的参数
+func (f *Framework) parseFlags() error {
+	if f.flagArgs == nil {
+		return nil
+	}
+
+	program, args := "app", f.flagArgs
+	if len(args) > 0 {
+		program, args = filepath.Base(args[0]), args[1:]
+	}
+
+	flags := pflag.NewFlagSet(program, pflag.ContinueOnError)
+	flags.SetInterspersed(false)
+	flags.SetOutput(io.Discard)
+	flags.Usage = func() {}
+
+	configPath := flags.String("config", f.configFile(), "path to config file")
+	f.app.configManager.BindFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		if !errors.Is(err, pflag.ErrHelp) {
+			return fmt.Errorf("invalid flags: %w", err)
+		}
+		f.flagHelp = true
+	}
+	f.flags = flags
+
+	if flags.Changed("config") {
+		f.replaceConfigFile(*configPath)
+	}
+	return nil
+}
+
+// configFile 返回 WithConfig 指定的配置文件，多次调用时返回最后一个
+func (f *Framework) configFile() string {
+	file := ""
+	for _, loader := range f.configLoaders {
+		if loader.file != "" {
+			file = loader.file
+		}
+	}
+	return file
+}
+
+// replaceConfigFile 用 path 替换 WithConfig 指定的配置文件，没有时追加到最后
+func (f *Framework) replaceConfigFile(path string) {
+	loaders := make([]configLoader, 0, len(f.configLoaders)+1)
+	replaced := false
+	for _, loader := range f.configLoaders {
+		if loader.file == "" {
+			loaders = append(loaders, loader)
+			continue
+		}
+		if !replaced {
+			loaders = append(loaders, configFileLoader(path))
+			replaced = true
+		}
+	}
+	if !replaced {
+		loaders = append(loaders, configFileLoader(path))
+	}
+	f.configLoaders = loaders
+}
+
+// BindFlags 为框架配置与已注册应用配置段的每个字段定义 --<key> 参数并绑定到配置
+// 需要在 flags.Parse 之前调用，已存在的同名参数不会被重复定义
+func (cm *ConfigManager) BindFlags(flags *pflag.FlagSet) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	for _, envVar := range cm.envVars(cm.EnvPrefix()) {
+		if flags.Lookup(envVar.Key) != nil {
+			continue
+		}
+		defineConfigFlag(flags, envVar)
+	}
+	cm.flags = flags
+	bindFlags(cm.viper, flags, cm.envVars(cm.EnvPrefix()))
+}
+
+// defineConfigFlag 按字段类型定义参数，默认值与配置默认值一致
+func defineConfigFlag(flags *pflag.FlagSet, envVar EnvVar) {
+	switch envVar.Type {
+	case "bool":
+		flags.Bool(envVar.Key, false, envVar.Usage)
+	case "int":
+		flags.Int(envVar.Key, 0, envVar.Usage)
+	case "float64":
+		flags.Float64(envVar.Key, 0, envVar.Usage)
+	case "duration":
+		flags.Duration(envVar.Key, 0, envVar.Usage)
+	case "[]string":
+		flags.StringSlice(envVar.Key, nil, envVar.Usage)
+	default:
+		flags.String(envVar.Key, "", envVar.Usage)
+	}
+
+	if envVar.Default == "" {
+		return
+	}
+	flag := flags.Lookup(envVar.Key)
+	if err := flag.Value.Set(envVar.Default); err == nil {
+		flag.DefValue = flag.Value.String()
+	}
+}
+
+func bindFlags(v *viper.Viper, flags *pflag.FlagSet, envVars []EnvVar) {
+	for _, envVar := range envVars {
+		if flag := flags.Lookup(envVar.Key); flag != nil {
+			// flag 非空时 BindPFlag 不会返回错误
+			_ = v.BindPFlag(envVar.Key, flag)
+		}
+	}
+}
+
+// flagOrigin 返回设置了 key 的命令行参数，未设置时返回空
+func (cm *ConfigManager) flagOrigin(key string) string {
+	if cm.flags == nil {
+		return ""
+	}
+	origin := ""
+	cm.flags.Visit(func(flag *pflag.Flag) {
+		if normalizeConfigKey(flag.Name) == key {
+			origin = "flag:--" + flag.Name
+		}
+	})
+	return origin
+}
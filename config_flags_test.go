@@ -0,0 +1,103 @@
+package orz
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestWithFlagsOverridesEnvAndFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "override.yaml")
+	writeConfigFile(t, path, "log:\n  level: warn\nserver:\n  addr: \":8081\"\n  health:\n    timeout: 5s\n")
+	t.Setenv("LOG_LEVEL", "error")
+	t.Setenv("SERVER_HEALTH_TIMEOUT", "7s")
+
+	var gotArgs []string
+	framework, err := NewFramework(
+		WithLogger(zap.NewNop()),
+		WithConfig(filepath.Join(t.TempDir(), "missing.yaml")),
+		WithFlags([]string{"svc", "--config", path, "--log.level=debug", "--server.ip_trust_list=10.0.0.0/8,127.0.0.1", "collect", "a", "--verbose"}),
+		func(f *Framework) error {
+			f.app.AddCommand(Command{Name: "collect", Run: func(ctx context.Context, app *App, args []string) error {
+				gotArgs = args
+				return nil
+			}})
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("NewFramework returned error: %v", err)
+	}
+
+	config := framework.app.GetConfig()
+	if config.Log.Level != "debug" {
+		t.Fatalf("expected flag to override env and file, got %q", config.Log.Level)
+	}
+	if config.Server.Addr != ":8081" || config.Server.Health.Timeout != 7*time.Second || config.Server.Health.LivenessPath != "/healthz" {
+		t.Fatalf("unexpected precedence: addr=%q timeout=%s liveness=%q", config.Server.Addr, config.Server.Health.Timeout, config.Server.Health.LivenessPath)
+	}
+	if !slices.Equal(config.Server.IPTrustList, []string{"10.0.0.0/8", "127.0.0.1"}) {
+		t.Fatalf("unexpected ip_trust_list %v", config.Server.IPTrustList)
+	}
+	if origin := framework.app.configManager.Origin("log.level"); origin != "flag:--log.level" {
+		t.Fatalf("unexpected origin %q", origin)
+	}
+
+	if err := framework.Execute([]string{"svc", "--log.level=debug", "collect", "ignored"}); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if !slices.Equal(gotArgs, []string{"a", "--verbose"}) {
+		t.Fatalf("expected arguments after the command to reach it, got %v", gotArgs)
+	}
+}
+
+func TestWithFlagsOverridesEnvForAppConfig(t *testing.T) {
+	t.Setenv("APP_PAYMENTS_TIMEOUT", "0")
+	t.Setenv("APP_PAYMENTS_PROVIDER", "adyen")
+
+	framework, err := NewFramework(
+		WithLogger(zap.NewNop()),
+		WithConfigValidator("payments", &paymentConfig{}),
+		WithFlags([]string{"svc", "--app.payments.timeout=30"}),
+	)
+	if err != nil {
+		t.Fatalf("expected validator to see the flag value, got %v", err)
+	}
+
+	config, err := AppConfigAs[paymentConfig](framework.app, "payments")
+	if err != nil {
+		t.Fatalf("AppConfigAs returned error: %v", err)
+	}
+	if config.Timeout != 30 || config.Provider != "adyen" {
+		t.Fatalf("expected flag to override env and env to apply to other keys, got %+v", config)
+	}
+}
+
+func TestWithFlagsHelpListsConfigFlags(t *testing.T) {
+	framework, out := newCommandFramework(t, WithFlags([]string{"svc", "--help"}))
+
+	if err := framework.Execute([]string{"svc", "--help"}); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	printed := out.String()
+	for _, expected := range []string{"Usage: svc [flags] [command]", "--server.addr string", "HTTP listen address (default \":8080\")", "--server.health.timeout duration", "--config string", "(default [password,token,authorization,secret])", "(default [/healthz,/readyz])"} {
+		if !strings.Contains(printed, expected) {
+			t.Fatalf("expected help to contain %q, got:\n%s", expected, printed)
+		}
+	}
+	if keys, err := framework.flags.GetStringSlice("log.redact_keys"); err != nil || len(keys) != len(defaultRedactKeys) {
+		t.Fatalf("expected slice default to keep each element, got %v (%v)", keys, err)
+	}
+}
+
+func TestWithFlagsRejectsUnknownFlags(t *testing.T) {
+	_, err := NewFramework(WithLogger(zap.NewNop()), WithFlags([]string{"svc", "--server.port=1"}))
+	if err == nil || !strings.Contains(err.Error(), "invalid flags: unknown flag: --server.port") {
+		t.Fatalf("expected unknown flag error, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package orz
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// ProfileEnv 未通过 WithProfile 指定 profile 时读取的环境变量
+	ProfileEnv = "ORZ_PROFILE"
+
+	// configDropInDir 与基础配置文件同目录的附加配置目录
+	configDropInDir = "conf.d"
+
+	originDefault = "default"
+	originMap     = "map"
+	originBytes   = "bytes"
+)
+
+// WithProfile 设置配置 profile，例如 staging、prod
+// 加载 config.yaml 时会再合并 config.<profile>.yaml，未设置时读取 ORZ_PROFILE 环境变量
+func WithProfile(name string) Option {
+	return func(f *Framework) error {
+		f.app.configManager.SetProfile(name)
+		return nil
+	}
+}
+
+// SetProfile 设置配置 profile，需要在 LoadFromFile 之前调用
+func (cm *ConfigManager) SetProfile(name string) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.profile = name
+}
+
+// Profile 返回当前生效的 profile，未设置时读取 ORZ_PROFILE 环境变量
+func (cm *ConfigManager) Profile() string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.activeProfile()
+}
+
+func (cm *ConfigManager) activeProfile() string {
+	if cm.profile != "" {
+		return cm.profile
+	}
+	return strings.TrimSpace(os.Getenv(ProfileEnv))
+}
+
+// configFilesFor 返回基础配置文件、profile 文件与 conf.d 中的文件，按合并顺序排列
+func (cm *ConfigManager) configFilesFor(base string) ([]string, error) {
+	files := []string{base}
+
+	if profile := cm.activeProfile(); profile != "" {
+		ext := filepath.Ext(base)
+		profileFile := strings.TrimSuffix(base, ext) + "." + profile + ext
+		if _, err := os.Stat(profileFile); err != nil {
+			return nil, fmt.Errorf("config file for profile %q does not exist: %s", profile, profileFile)
+		}
+		files = append(files, profileFile)
+	}
+
+	var dropIns []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(filepath.Dir(base), configDropInDir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		dropIns = append(dropIns, matches...)
+	}
+	sort.Strings(dropIns)
+
+	return append(files, dropIns...), nil
+}
+
+// loadFiles 依次读取并合并配置文件，同时记录每个键的来源
+func (cm *ConfigManager) loadFiles(v *viper.Viper, state *configState, base string) error {
+	files, err := cm.configFilesFor(base)
+	if err != nil {
+		return err
+	}
+
+	for i, file := range files {
+		v.SetConfigFile(file)
+		read := v.MergeInConfig
+		if i == 0 {
+			read = v.ReadInConfig
+		}
+		if err := read(); err != nil {
+			return fmt.Errorf("failed to read config file %s: %w", file, err)
+		}
+		if i == 0 {
+			// ReadInConfig 会替换之前加载的全部配置
+			state.origins = make(map[string]string)
+		}
+		if err := state.recordFile(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *configState) recordFile(file string) error {
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", file, err)
+	}
+	s.files = append(s.files, file)
+	s.record(v, file)
+	return nil
+}
+
+func (s *configState) recordBytes(data []byte) error {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return err
+	}
+	// ReadConfig 会替换之前加载的全部配置
+	s.origins = make(map[string]string)
+	s.record(v, originBytes)
+	return nil
+}
+
+func (s *configState) recordMap(data map[string]interface{}) error {
+	v := viper.New()
+	if err := v.MergeConfigMap(data); err != nil {
+		return err
+	}
+	s.record(v, originMap)
+	return nil
+}
+
+func (s *configState) record(v *viper.Viper, origin string) {
+	for _, key := range v.AllKeys() {
+		s.origins[normalizeConfigKey(key)] = origin
+	}
+}
+
+// Origin 返回配置项的来源：flag:--<参数名>、env:<变量名>、配置文件路径、map、bytes 或 default
+func (cm *ConfigManager) Origin(key string) string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	normalized := normalizeConfigKey(key)
+	if origin := cm.flagOrigin(normalized); origin != "" {
+		return origin
+	}
+
+	// AutomaticEnv 只对 viper 已知的键生效，环境变量名由已知键名转换而来
+	for _, known := range cm.viper.AllKeys() {
+		if normalizeConfigKey(known) != normalized {
+			continue
+		}
+		if name := configEnvName(cm.EnvPrefix(), known); os.Getenv(name) != "" {
+			if file, ok := cm.dotEnvVars[name]; ok {
+				return "env:" + name + " (" + file + ")"
+			}
+			return "env:" + name
+		}
+	}
+	if origin, ok := cm.state.origins[normalized]; ok {
+		return origin
+	}
+	return originDefault
+}
+
+// normalizeConfigKey 按段归一化配置键，规则与字段名匹配相同
+func normalizeConfigKey(key string) string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		parts[i] = normalizeConfigName(part)
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,129 @@
+package orz
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func writeProfileFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		writeConfigFile(t, path, content)
+	}
+	return dir
+}
+
+func TestLoadFromFileMergesProfileAndDropIns(t *testing.T) {
+	dir := writeProfileFiles(t, map[string]string{
+		"config.yaml":          "server:\n  addr: \":8080\"\nlog:\n  level: info\napp:\n  name: base\n  region: us\n",
+		"config.prod.yaml":     "log:\n  level: warn\napp:\n  name: prod\n",
+		"conf.d/20-region.yml": "app:\n  region: eu\n",
+		"conf.d/10-name.yaml":  "app:\n  name: drop-in\n",
+		"conf.d/ignored.txt":   "app:\n  name: ignored\n",
+	})
+
+	cm := NewConfigManager()
+	cm.SetProfile("prod")
+	if err := cm.LoadFromFile(filepath.Join(dir, "config.yaml")); err != nil {
+		t.Fatalf("LoadFromFile returned error: %v", err)
+	}
+
+	config := cm.GetConfig()
+	if config.Log.Level != "warn" || config.App["name"] != "drop-in" || config.App["region"] != "eu" {
+		t.Fatalf("unexpected merged config: level=%q app=%v", config.Log.Level, config.App)
+	}
+	if files := cm.ConfigFiles(); len(files) != 4 || !strings.HasSuffix(files[2], "10-name.yaml") {
+		t.Fatalf("unexpected loaded files: %v", files)
+	}
+
+	origins := map[string]string{
+		"server.addr":   "config.yaml",
+		"log.level":     "config.prod.yaml",
+		"app.name":      "10-name.yaml",
+		"app.region":    "20-region.yml",
+		"log.max_size":  originDefault,
+		"database.type": originDefault,
+	}
+	for key, expected := range origins {
+		if got := cm.Origin(key); !strings.HasSuffix(got, expected) {
+			t.Fatalf("Origin(%q) = %q, want suffix %q", key, got, expected)
+		}
+	}
+
+	t.Setenv("SERVER_ADDR", ":9090")
+	if got := cm.Origin("server.addr"); got != "env:SERVER_ADDR" {
+		t.Fatalf("expected env origin, got %q", got)
+	}
+}
+
+func TestLoadFromFileUsesProfileEnv(t *testing.T) {
+	dir := writeProfileFiles(t, map[string]string{
+		"config.yaml":         "log:\n  level: info\n",
+		"config.staging.yaml": "log:\n  level: debug\n",
+	})
+	t.Setenv(ProfileEnv, "staging")
+
+	cm := NewConfigManager()
+	if err := cm.LoadFromFile(filepath.Join(dir, "config.yaml")); err != nil {
+		t.Fatalf("LoadFromFile returned error: %v", err)
+	}
+	if cm.Profile() != "staging" || cm.GetConfig().Log.Level != "debug" {
+		t.Fatalf("expected staging profile to apply, got profile=%q level=%q", cm.Profile(), cm.GetConfig().Log.Level)
+	}
+}
+
+func TestLoadFromFileRejectsMissingProfile(t *testing.T) {
+	dir := writeProfileFiles(t, map[string]string{"config.yaml": "log:\n  level: info\n"})
+
+	cm := NewConfigManager()
+	cm.SetProfile("prod")
+	err := cm.LoadFromFile(filepath.Join(dir, "config.yaml"))
+	if err == nil || !strings.Contains(err.Error(), `profile "prod"`) {
+		t.Fatalf("expected missing profile error, got %v", err)
+	}
+}
+
+func TestExecuteConfigPrintWithOrigin(t *testing.T) {
+	dir := writeProfileFiles(t, map[string]string{
+		"config.yaml":      "log:\n  level: info\n",
+		"config.prod.yaml": "log:\n  level: warn\n",
+	})
+
+	framework, err := NewFramework(
+		WithLogger(zap.NewNop()),
+		WithProfile("prod"),
+		WithConfig(filepath.Join(dir, "config.yaml")),
+		WithConfigMap(map[string]interface{}{"server": map[string]interface{}{"addr": ":9999"}}),
+	)
+	if err != nil {
+		t.Fatalf("NewFramework returned error: %v", err)
+	}
+	out := &bytes.Buffer{}
+	framework.stdout = out
+
+	if err := framework.Execute([]string{"svc", "config", "print", "--origin"}); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+
+	printed := out.String()
+	for _, expected := range []string{
+		"level: warn # " + filepath.Join(dir, "config.prod.yaml"),
+		"max_size: 100 # default",
+		"addr: :9999 # map",
+	} {
+		if !strings.Contains(printed, expected) {
+			t.Fatalf("expected output to contain %q, got:\n%s", expected, printed)
+		}
+	}
+}
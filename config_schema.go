@@ -0,0 +1,300 @@
+package orz
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// configSchemaDialect 生成的 JSON Schema 版本
+const configSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// durationPattern 匹配 time.ParseDuration 接受的时长字符串
+const durationPattern = `^-?([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$|^0$`
+
+// ConfigSchemaProvider 自定义配置段的 JSON Schema
+// 通过 BindAppConfig 注册的类型实现该接口时，使用其返回值代替自动生成的 schema
+type ConfigSchemaProvider interface {
+	ConfigSchema() map[string]any
+}
+
+// JSONSchema 返回配置文件的 JSON Schema
+// 包括框架配置的类型、枚举、默认值与说明，以及通过 BindAppConfig 注册的 app 配置段
+func (cm *ConfigManager) JSONSchema() map[string]any {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	g := &schemaGenerator{defs: make(map[string]any), visiting: make(map[reflect.Type]bool)}
+	schema := g.structSchema(reflect.TypeFor[Config](), "", true)
+
+	for _, envType := range cm.envTypes {
+		embedSchema(schema, envType.path, g.typeSchema(envType.typ, envType.path, false))
+	}
+
+	schema["$schema"] = configSchemaDialect
+	schema["title"] = "orz config"
+	if len(g.defs) > 0 {
+		schema["$defs"] = g.defs
+	}
+	return schema
+}
+
+type schemaGenerator struct {
+	defs     map[string]any
+	visiting map[reflect.Type]bool
+}
+
+// typeSchema 生成 t 的 schema，path 用于查找框架配置的默认值，strict 表示结构体不允许未知字段
+func (g *schemaGenerator) typeSchema(t reflect.Type, path string, strict bool) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if provider, ok := reflect.New(t).Interface().(ConfigSchemaProvider); ok {
+		return provider.ConfigSchema()
+	}
+
+	switch {
+	case t == reflect.TypeFor[time.Duration]():
+		return map[string]any{"type": []string{"string", "integer"}, "pattern": durationPattern}
+	case t == reflect.TypeFor[time.Time]():
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == reflect.TypeFor[DatabaseType]():
+		return map[string]any{"type": "string", "enum": databaseTypeNames()}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if g.visiting[t] {
+			return g.ref(t, strict)
+		}
+		return g.structSchema(t, path, strict)
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.typeSchema(t.Elem(), "", strict)}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.typeSchema(t.Elem(), "", strict)}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	default:
+		return map[string]any{}
+	}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type, path string, strict bool) map[string]any {
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	properties := make(map[string]any)
+	schema := map[string]any{"type": "object", "properties": properties}
+	if strict {
+		schema["additionalProperties"] = false
+	}
+
+	for _, f := range configFields(t) {
+		if f.key == "" {
+			embedded := g.typeSchema(f.field.Type, path, strict)
+			if fields, ok := embedded["properties"].(map[string]any); ok {
+				for key, value := range fields {
+					properties[key] = value
+				}
+			}
+			continue
+		}
+
+		key := joinConfigKey(path, f.key)
+		field := g.typeSchema(f.field.Type, key, strict)
+		if t == reflect.TypeFor[Config]() && f.key == "app" {
+			// app 段由应用自行定义，未注册的键不做限制
+			field = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+		if usage := f.field.Tag.Get("usage"); usage != "" {
+			field["description"] = usage
+		}
+		if enum := f.field.Tag.Get("enum"); enum != "" {
+			field["enum"] = strings.Split(enum, ",")
+		}
+		if value, ok := schemaDefault(f.field, key); ok {
+			field["default"] = value
+		}
+		properties[f.key] = field
+	}
+	return schema
+}
+
+// ref 返回递归结构的引用，定义放在 $defs 中，不带路径相关的默认值
+func (g *schemaGenerator) ref(t reflect.Type, strict bool) map[string]any {
+	name := t.Name()
+	if _, ok := g.defs[name]; !ok {
+		g.defs[name] = map[string]any{}
+		visiting := g.visiting
+		g.visiting = map[reflect.Type]bool{}
+		g.defs[name] = g.structSchema(t, "", strict)
+		g.visiting = visiting
+	}
+	return map[string]any{"$ref": "#/$defs/" + name}
+}
+
+// schemaDefault 返回字段默认值：框架配置取自 NewConfigManager 的默认值，其余取自 default 标签
+func schemaDefault(field reflect.StructField, key string) (any, bool) {
+	if value, ok := configDefaults[key]; ok {
+		return schemaValue(reflect.ValueOf(value), field.Type), true
+	}
+
+	tag, ok := field.Tag.Lookup("default")
+	if !ok {
+		return nil, false
+	}
+	value := reflect.New(field.Type)
+	if err := decodeConfigValue(tag, value.Interface()); err != nil {
+		return tag, true
+	}
+	return schemaValue(value.Elem(), field.Type), true
+}
+
+// schemaValue 将默认值转换为 JSON 中的表示，时长使用 1m30s 这样的字符串
+func schemaValue(value reflect.Value, t reflect.Type) any {
+	if t == reflect.TypeFor[time.Duration]() {
+		if value.CanInt() {
+			return time.Duration(value.Int()).String()
+		}
+	}
+	return value.Interface()
+}
+
+// embedSchema 将注册的配置段 schema 放到 root 的 path 下，例如 app.payments
+// 目标已存在时合并属性，因此 BindAppConfig("", ...) 注册的类型会合并到 app 段本身
+func embedSchema(root map[string]any, path string, schema map[string]any) {
+	current := root
+	for _, part := range strings.Split(path, ".") {
+		properties, ok := current["properties"].(map[string]any)
+		if !ok {
+			properties = make(map[string]any)
+			current["properties"] = properties
+		}
+		next, ok := properties[part].(map[string]any)
+		if !ok {
+			next = map[string]any{"type": "object"}
+			properties[part] = next
+		}
+		current = next
+	}
+
+	for key, value := range schema {
+		if key == "properties" {
+			properties, ok := current["properties"].(map[string]any)
+			if !ok {
+				properties = make(map[string]any)
+				current["properties"] = properties
+			}
+			for name, property := range value.(map[string]any) {
+				properties[name] = property
+			}
+			continue
+		}
+		current[key] = value
+	}
+}
+
+// databaseTypeNames 返回内置与已注册的数据库类型
+func databaseTypeNames() []string {
+	names := []string{string(DatabaseSqlite), string(DatabaseMysql), string(DatabasePostgres), string(DatabasePostgresql)}
+	for _, driver := range RegisteredDatabaseDrivers() {
+		if !slices.Contains(names, string(driver)) {
+			names = append(names, string(driver))
+		}
+	}
+	return names
+}
+
+// validateKnownKeys 按 schema 检查配置中的未知键，键名匹配规则与解析配置时相同
+func validateKnownKeys(v *Validation, schema map[string]any, value any, defs map[string]any) {
+	if ref, ok := schema["$ref"].(string); ok {
+		if def, ok := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any); ok {
+			schema = def
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		additional, _ := schema["additionalProperties"].(map[string]any)
+		strict := schema["additionalProperties"] == false
+		for _, key := range sortedKeys(value) {
+			if property, ok := lookupSchemaProperty(properties, key); ok {
+				validateKnownKeys(v.At(key), property, value[key], defs)
+				continue
+			}
+			switch {
+			case additional != nil:
+				validateKnownKeys(v.At(key), additional, value[key], defs)
+			case strict:
+				v.Errorf(key, "unknown key")
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range value {
+				validateKnownKeys(v.At(fmt.Sprintf("[%d]", i)), items, item, defs)
+			}
+		}
+	}
+}
+
+func lookupSchemaProperty(properties map[string]any, key string) (map[string]any, bool) {
+	for name, property := range properties {
+		if matchConfigName(name, key) {
+			schema, ok := property.(map[string]any)
+			return schema, ok
+		}
+	}
+	return nil, false
+}
+
+// ValidateConfigFile 离线校验配置文件：检查未知键、引用与类型，并执行框架与 app 配置段的校验规则
+// 使用与当前配置相同的 profile、环境变量前缀、加密密钥与已注册的 app 配置段，不影响当前配置
+func (a *App) ValidateConfigFile(path string) error {
+	cm := a.configManager.derive()
+	if err := cm.LoadFromFile(path); err != nil {
+		return err
+	}
+
+	v := NewValidation()
+	schema := cm.JSONSchema()
+	defs, _ := schema["$defs"].(map[string]any)
+	cm.mu.RLock()
+	settings := cm.viper.AllSettings()
+	cm.mu.RUnlock()
+	validateKnownKeys(v, schema, settings, defs)
+
+	config, err := cm.Decode()
+	if err != nil {
+		return err
+	}
+	a.collectConfigErrors(v, config)
+	return v.Err()
+}
+
+// derive 创建使用相同 profile、环境变量前缀、加密密钥与 app 配置段的空配置管理器
+func (cm *ConfigManager) derive() *ConfigManager {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	derived := &ConfigManager{
+		state:    newConfigState(),
+		profile:  cm.profile,
+		keyFile:  cm.keyFile,
+		envTypes: slices.Clone(cm.envTypes),
+	}
+	derived.envPrefix.Store(cm.envPrefix.Load())
+	derived.bound.Store(cm.bound.Load())
+	derived.viper = derived.newViper()
+	return derived
+}
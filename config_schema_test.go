@@ -0,0 +1,188 @@
+package orz
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type schemaSearchConfig struct {
+	Endpoint string `mapstructure:"endpoint" usage:"search endpoint"`
+	Mode     string `mapstructure:"mode" enum:"fast,exact" default:"fast"`
+	Limits   struct {
+		MaxResults int `mapstructure:"max_results" default:"50"`
+	} `mapstructure:"limits"`
+}
+
+func (c schemaSearchConfig) ValidateConfig(v *Validation) {
+	v.Required("endpoint", c.Endpoint)
+}
+
+// schemaProperty 按路径返回 schema 中的属性
+func schemaProperty(t *testing.T, schema map[string]any, path string) map[string]any {
+	t.Helper()
+
+	current := schema
+	for _, part := range strings.Split(path, ".") {
+		properties, ok := current["properties"].(map[string]any)
+		if !ok {
+			t.Fatalf("schema at %q has no properties", path)
+		}
+		if current, ok = properties[part].(map[string]any); !ok {
+			t.Fatalf("schema has no property %q", path)
+		}
+	}
+	return current
+}
+
+func TestJSONSchemaDescribesConfig(t *testing.T) {
+	cm := NewConfigManager()
+	cm.BindEnv("app.search", &schemaSearchConfig{})
+	schema := cm.JSONSchema()
+
+	if schema["$schema"] != configSchemaDialect || schema["additionalProperties"] != false {
+		t.Fatalf("unexpected schema header: %v", schema)
+	}
+
+	level := schemaProperty(t, schema, "log.level")
+	if level["default"] != "info" || !slices.Contains(level["enum"].([]string), "warn") || level["description"] == "" {
+		t.Fatalf("unexpected log.level schema: %v", level)
+	}
+	if databaseType := schemaProperty(t, schema, "database.type"); !slices.Contains(databaseType["enum"].([]string), "mysql") || databaseType["default"] != "sqlite" {
+		t.Fatalf("unexpected database.type schema: %v", databaseType)
+	}
+	if timeout := schemaProperty(t, schema, "server.health.timeout"); timeout["default"] != "3s" || timeout["pattern"] == nil {
+		t.Fatalf("unexpected duration schema: %v", timeout)
+	}
+	if replicas := schemaProperty(t, schema, "database.replicas"); replicas["items"].(map[string]any)["$ref"] != "#/$defs/DatabaseConfig" {
+		t.Fatalf("expected replicas to reference DatabaseConfig, got %v", replicas)
+	}
+
+	if mode := schemaProperty(t, schema, "app.search.mode"); mode["default"] != "fast" || !slices.Equal(mode["enum"].([]string), []string{"fast", "exact"}) {
+		t.Fatalf("unexpected app section schema: %v", mode)
+	}
+	if maxResults := schemaProperty(t, schema, "app.search.limits.max_results"); maxResults["default"] != 50 || maxResults["type"] != "integer" {
+		t.Fatalf("unexpected nested app schema: %v", maxResults)
+	}
+
+	if _, err := json.Marshal(schema); err != nil {
+		t.Fatalf("schema is not JSON encodable: %v", err)
+	}
+}
+
+func TestValidateConfigFileReportsProblems(t *testing.T) {
+	app := NewApp()
+	app.AddConfigValidator("search", &schemaSearchConfig{})
+
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.yaml")
+	writeConfigFile(t, invalid, `
+log:
+  levl: debug
+database:
+  replica_policy: sticky
+  replicas:
+    - url: "file:replica.db"
+      pasword: x
+app:
+  search:
+    mode: fast
+  other: free-form
+`)
+	err := app.ValidateConfigFile(invalid)
+	var validationErrors ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	for _, expected := range []string{
+		"log.levl: unknown key",
+		"database.replicas[0].pasword: unknown key",
+		"database.replica_policy: must be one of",
+		"app.search.endpoint: is required",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected error to contain %q, got %v", expected, err)
+		}
+	}
+	if strings.Contains(err.Error(), "app.other") {
+		t.Fatalf("expected unregistered app keys to be allowed, got %v", err)
+	}
+
+	valid := filepath.Join(dir, "valid.yaml")
+	writeConfigFile(t, valid, "server:\n  addr: \":9000\"\napp:\n  search:\n    endpoint: http://search\n")
+	if err := app.ValidateConfigFile(valid); err != nil {
+		t.Fatalf("ValidateConfigFile returned error: %v", err)
+	}
+	if app.GetConfig().Server.Addr != ":8080" {
+		t.Fatalf("expected current config to be unaffected")
+	}
+}
+
+func TestExecuteConfigSchemaAndValidate(t *testing.T) {
+	framework, out := newCommandFramework(t)
+
+	if err := framework.Execute([]string{"svc", "config", "schema"}); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(out.Bytes(), &schema); err != nil || schema["title"] != "orz config" {
+		t.Fatalf("expected JSON schema output, got %v: %s", err, out.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, "log:\n  level: debug\n")
+	out.Reset()
+	if err := framework.Execute([]string{"svc", "config", "validate", path}); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), path+": ok") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
+
+func TestExecuteConfigCommandsSkipDatabaseAndHTTP(t *testing.T) {
+	withIsolatedDatabaseDrivers(t)
+	RegisterDatabaseDriver(func(cfg DatabaseConfig, logger gormlogger.Interface) (*gorm.DB, error) {
+		return nil, errors.New("database unreachable")
+	}, DatabaseType("stub"))
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, "server:\n  addr: \":9000\"\n")
+
+	framework, err := NewFramework(
+		WithConfigMap(map[string]interface{}{
+			"database": map[string]interface{}{"type": "stub"},
+		}),
+		WithDatabase(),
+		WithHTTP(),
+	)
+	if err != nil {
+		t.Fatalf("NewFramework returned error: %v", err)
+	}
+
+	out := &bytes.Buffer{}
+	framework.stdout = out
+	if err := framework.Execute([]string{"svc", "config", "validate", path}); err != nil {
+		t.Fatalf("expected config commands not to connect the database, got %v", err)
+	}
+	if !strings.Contains(out.String(), path+": ok") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+	if framework.GetDB() != nil || framework.GetEcho() != nil {
+		t.Fatal("expected config commands to skip database and HTTP initialization")
+	}
+
+	if err := framework.Execute([]string{"svc", "config", "schema"}); err != nil {
+		t.Fatalf("config schema returned error: %v", err)
+	}
+	if err := framework.Execute([]string{"svc", "serve"}); err == nil || !strings.Contains(err.Error(), "database unreachable") {
+		t.Fatalf("expected serve to complete initialization and connect the database, got %v", err)
+	}
+}
@@ -0,0 +1,271 @@
+package orz
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// minSecretLength 短于该长度的值不参与日志脱敏，避免误替换普通文本
+const minSecretLength = 4
+
+// resolveConfigReferences 解析配置中所有字符串里的 ${env:NAME}、${env:NAME:-default} 与 ${file:path} 引用，
+// 并解密 ENC(...) 值，keyFile 为空时从环境变量读取密钥。
+// 返回解析后的配置以及需要脱敏的值：解密得到的值、从文件读取的值，以及敏感键（密码、令牌、连接串等）中引用的值
+func resolveConfigReferences(settings map[string]any, keyFile string) (map[string]any, []string, error) {
+	r := &referenceResolver{validation: NewValidation(), keyFile: keyFile}
+	resolved := r.resolveMap(settings, "")
+	if err := r.validation.Err(); err != nil {
+		return nil, nil, err
+	}
+	return resolved, r.secrets, nil
+}
+
+type referenceResolver struct {
+	validation *Validation
+	secrets    []string
+	keyFile    string
+	keyring    configKeyring
+	keyringErr error
+}
+
+func (r *referenceResolver) resolveMap(values map[string]any, path string) map[string]any {
+	result := make(map[string]any, len(values))
+	for key, value := range values {
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+		result[key] = r.resolveValue(value, childPath, key)
+	}
+	return result
+}
+
+func (r *referenceResolver) resolveValue(value any, path, key string) any {
+	switch value := value.(type) {
+	case map[string]any:
+		return r.resolveMap(value, path)
+	case []any:
+		result := make([]any, len(value))
+		for i, item := range value {
+			result[i] = r.resolveValue(item, fmt.Sprintf("%s[%d]", path, i), key)
+		}
+		return result
+	case []string:
+		result := make([]string, len(value))
+		for i, item := range value {
+			result[i], _ = r.resolveValue(item, fmt.Sprintf("%s[%d]", path, i), key).(string)
+		}
+		return result
+	case string:
+		resolved, references, err := interpolate(value)
+		if err != nil {
+			r.validation.Errorf(path, "%v", err)
+			return value
+		}
+		sensitive := isSensitiveKey(key) || strings.EqualFold(key, "url") || strings.EqualFold(key, "dsn")
+		for _, reference := range references {
+			if reference.fromFile || sensitive {
+				r.secrets = append(r.secrets, reference.value)
+			}
+		}
+		if _, ok := encryptedPayload(resolved); ok {
+			decrypted, err := r.decryptValue(resolved)
+			if err != nil {
+				r.validation.Errorf(path, "%v", err)
+				return value
+			}
+			r.secrets = append(r.secrets, decrypted)
+			return decrypted
+		}
+		return resolved
+	default:
+		return value
+	}
+}
+
+// resolvedReference 从环境变量或文件读取到的引用值，不包括 :-default 默认值
+type resolvedReference struct {
+	value    string
+	fromFile bool
+}
+
+// interpolate 替换字符串中的引用，$${ 表示字面量 ${
+func interpolate(value string) (string, []resolvedReference, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil, nil
+	}
+
+	var b strings.Builder
+	var references []resolvedReference
+	for {
+		start := strings.Index(value, "${")
+		if start < 0 {
+			b.WriteString(value)
+			break
+		}
+		if start > 0 && value[start-1] == '$' {
+			b.WriteString(value[:start-1])
+			b.WriteString("${")
+			value = value[start+2:]
+			continue
+		}
+
+		end := strings.IndexByte(value[start:], '}')
+		if end < 0 {
+			return "", nil, fmt.Errorf("unterminated reference in %q", value)
+		}
+		reference := value[start+2 : start+end]
+		resolved, found, err := resolveReference(reference)
+		if err != nil {
+			return "", nil, err
+		}
+		if found != nil {
+			references = append(references, *found)
+		}
+
+		b.WriteString(value[:start])
+		b.WriteString(resolved)
+		value = value[start+end+1:]
+	}
+	return b.String(), references, nil
+}
+
+// resolveReference 解析单个引用，支持 env:NAME、file:path，以及 :-default 默认值
+// 使用默认值时返回的 resolvedReference 为 nil
+func resolveReference(reference string) (string, *resolvedReference, error) {
+	scheme, target, ok := strings.Cut(reference, ":")
+	if !ok {
+		return "", nil, fmt.Errorf("invalid reference ${%s}, expected ${env:NAME} or ${file:path}", reference)
+	}
+	target, defaultValue, hasDefault := strings.Cut(target, ":-")
+
+	switch scheme {
+	case "env":
+		if value := os.Getenv(target); value != "" {
+			return value, &resolvedReference{value: value}, nil
+		}
+		if hasDefault {
+			return defaultValue, nil, nil
+		}
+		return "", nil, fmt.Errorf("environment variable %s referenced by ${%s} is not set", target, reference)
+	case "file":
+		data, err := os.ReadFile(target)
+		if err != nil {
+			if hasDefault {
+				return defaultValue, nil, nil
+			}
+			return "", nil, fmt.Errorf("cannot read file referenced by ${%s}: %w", reference, err)
+		}
+		value := strings.TrimRight(string(data), "\r\n")
+		return value, &resolvedReference{value: value, fromFile: true}, nil
+	default:
+		return "", nil, fmt.Errorf("unknown reference scheme %q in ${%s}", scheme, reference)
+	}
+}
+
+// setSecrets 记录最近一次解析得到的敏感值，按长度降序排列以便先替换较长的值
+func (cm *ConfigManager) setSecrets(secrets []string) {
+	filtered := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		if len(secret) >= minSecretLength {
+			filtered = append(filtered, secret)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		return len(filtered[i]) > len(filtered[j])
+	})
+	cm.secrets.Store(&filtered)
+}
+
+// RedactSecrets 将字符串中通过引用解析出的敏感值替换为 ******
+func (cm *ConfigManager) RedactSecrets(s string) string {
+	secrets := cm.secrets.Load()
+	if secrets == nil {
+		return s
+	}
+	for _, secret := range *secrets {
+		if strings.Contains(s, secret) {
+			s = strings.ReplaceAll(s, secret, redactedValue)
+		}
+	}
+	return s
+}
+
+// withSecretRedaction 包装日志器，输出前隐藏配置中解析出的敏感值
+func (a *App) withSecretRedaction(logger *zap.Logger) *zap.Logger {
+	redact := a.configManager.RedactSecrets
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &redactingCore{Core: core, redact: redact}
+	}))
+}
+
+// redactingCore 对日志消息与字符串字段脱敏
+type redactingCore struct {
+	zapcore.Core
+	redact func(string) string
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(c.redactFields(fields)), redact: c.redact}
+}
+
+// Check 由内层 Core 决定是否写入，使其中的按名称级别、采样与限流生效
+// 内层接受的条目在写入时先脱敏再交给内层写出
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	inner := c.Core.Check(entry, nil)
+	if inner == nil {
+		return checked
+	}
+	return checked.AddCore(entry, &redactedEntry{redactingCore: c, inner: inner})
+}
+
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = c.redact(entry.Message)
+	return c.Core.Write(entry, c.redactFields(fields))
+}
+
+// redactedEntry 内层 Core 已接受的日志条目，写入时脱敏后通过内层条目写出
+type redactedEntry struct {
+	*redactingCore
+	inner *zapcore.CheckedEntry
+}
+
+func (e *redactedEntry) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	e.inner.Message = e.redact(entry.Message)
+	e.inner.Write(e.redactFields(fields)...)
+	return nil
+}
+
+func (c *redactingCore) redactFields(fields []zapcore.Field) []zapcore.Field {
+	var result []zapcore.Field
+	for i, field := range fields {
+		redacted, changed := field, false
+		switch field.Type {
+		case zapcore.StringType:
+			redacted.String = c.redact(field.String)
+			changed = redacted.String != field.String
+		case zapcore.ErrorType:
+			if err, ok := field.Interface.(error); ok {
+				if message := c.redact(err.Error()); message != err.Error() {
+					redacted, changed = zap.String(field.Key, message), true
+				}
+			}
+		}
+		if !changed {
+			continue
+		}
+		if result == nil {
+			result = append([]zapcore.Field(nil), fields...)
+		}
+		result[i] = redacted
+	}
+	if result == nil {
+		return fields
+	}
+	return result
+}
@@ -0,0 +1,153 @@
+package orz
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestInterpolate(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "db_password")
+	writeConfigFile(t, secretFile, "s3cr3t\n")
+	t.Setenv("ORZ_TEST_HOST", "db.internal")
+	t.Setenv("ORZ_TEST_EMPTY", "")
+
+	tests := []struct {
+		input    string
+		expected string
+		fromFile bool
+	}{
+		{input: "plain", expected: "plain"},
+		{input: "${env:ORZ_TEST_HOST}", expected: "db.internal"},
+		{input: "tcp(${env:ORZ_TEST_HOST}:${env:ORZ_TEST_PORT:-3306})", expected: "tcp(db.internal:3306)"},
+		{input: "${env:ORZ_TEST_EMPTY:-fallback}", expected: "fallback"},
+		{input: "${file:" + secretFile + "}", expected: "s3cr3t", fromFile: true},
+		{input: "${file:/does/not/exist:-none}", expected: "none"},
+		{input: "$${env:ORZ_TEST_HOST}", expected: "${env:ORZ_TEST_HOST}"},
+	}
+	for _, tt := range tests {
+		got, references, err := interpolate(tt.input)
+		if err != nil {
+			t.Fatalf("interpolate(%q) returned error: %v", tt.input, err)
+		}
+		fromFile := len(references) == 1 && references[0].fromFile
+		if got != tt.expected || fromFile != tt.fromFile {
+			t.Fatalf("interpolate(%q) = %q, %v, want %q, %v", tt.input, got, fromFile, tt.expected, tt.fromFile)
+		}
+	}
+}
+
+func TestDecodeReportsMissingReferences(t *testing.T) {
+	cm := NewConfigManager()
+	if err := cm.LoadFromMap(map[string]interface{}{
+		"server": map[string]interface{}{"addr": "${env:ORZ_TEST_MISSING}"},
+		"app":    map[string]interface{}{"key": "${file:/does/not/exist}", "mode": "${vault:x}"},
+	}); err != nil {
+		t.Fatalf("LoadFromMap returned error: %v", err)
+	}
+
+	_, err := cm.Decode()
+	var validationErrors ValidationErrors
+	if !errors.As(err, &validationErrors) || len(validationErrors) != 3 {
+		t.Fatalf("expected three reference errors, got %v", err)
+	}
+	for _, expected := range []string{"server.addr: environment variable ORZ_TEST_MISSING", "app.key: cannot read file", `app.mode: unknown reference scheme "vault"`} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected error to contain %q, got %v", expected, err)
+		}
+	}
+	if cm.GetConfig() != nil {
+		t.Fatalf("expected GetConfig to return nil for unresolved references")
+	}
+}
+
+func TestExecuteConfigPrintRedactsResolvedSecrets(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "signing_key")
+	writeConfigFile(t, secretFile, "k3y-from-file\n")
+	t.Setenv("ORZ_TEST_DB_PASSWORD", "pa55word")
+
+	framework, out := newCommandFramework(t, WithConfigMap(map[string]interface{}{
+		"database": map[string]interface{}{
+			"url": "root:${env:ORZ_TEST_DB_PASSWORD}@tcp(127.0.0.1:3306)/app",
+		},
+		"app": map[string]interface{}{
+			"signing": "${file:" + secretFile + "}",
+			"banner":  "key=${file:" + secretFile + "}",
+		},
+	}))
+
+	if err := framework.Execute([]string{"svc", "config", "print"}); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	printed := out.String()
+	if strings.Contains(printed, "k3y-from-file") || strings.Contains(printed, "pa55word") {
+		t.Fatalf("expected resolved secrets to be redacted, got:\n%s", printed)
+	}
+	if !strings.Contains(printed, "banner: key=******") {
+		t.Fatalf("expected embedded secret to be redacted, got:\n%s", printed)
+	}
+}
+
+func TestLoggerRedactsResolvedSecrets(t *testing.T) {
+	t.Setenv("ORZ_TEST_API_TOKEN", "tok-123456")
+
+	app := NewApp()
+	if err := app.LoadConfigFromMap(map[string]interface{}{
+		"app": map[string]interface{}{"api_token": "${env:ORZ_TEST_API_TOKEN}"},
+	}); err != nil {
+		t.Fatalf("LoadConfigFromMap returned error: %v", err)
+	}
+	if _, err := app.configManager.Decode(); err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := app.withSecretRedaction(zap.New(core)).With(zap.String("token", "tok-123456"))
+	logger.Info("calling api with tok-123456", zap.Error(errors.New("rejected tok-123456")))
+
+	entry := logs.All()[0]
+	if strings.Contains(entry.Message, "tok-123456") {
+		t.Fatalf("expected message to be redacted, got %q", entry.Message)
+	}
+	for key, value := range entry.ContextMap() {
+		if strings.Contains(value.(string), "tok-123456") {
+			t.Fatalf("expected field %s to be redacted, got %q", key, value)
+		}
+	}
+}
+
+// hookedCore 在 Check 中为条目注册写入后回调，用于确认内层条目被写出
+type hookedCore struct {
+	zapcore.Core
+	written *int
+}
+
+func (c hookedCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(entry.Level) {
+		return checked
+	}
+	return checked.AddCore(entry, c).After(entry, c)
+}
+
+func (c hookedCore) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	*c.written++
+}
+
+func TestLoggerRedactionWritesThroughInnerEntry(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	written := 0
+	logger := NewApp().withSecretRedaction(zap.New(hookedCore{Core: core, written: &written}))
+
+	logger.Debug("filtered")
+	logger.Info("first")
+	logger.Info("second")
+
+	if written != 2 || logs.Len() != 2 {
+		t.Fatalf("expected the inner entries to be written once each, got %d hooks and %d logs", written, logs.Len())
+	}
+}
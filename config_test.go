@@ -156,3 +156,30 @@ LOG:
 		t.Fatalf("expected LOG.MAXSIZE to be loaded, got %d", cfg.Log.MaxSize)
 	}
 }
+
+func TestLoadConfigFromBytesPreservesNamedDatabaseKeys(t *testing.T) {
+	app := NewApp()
+	err := app.LoadConfigFromBytes([]byte(`
+databases:
+  analytics_db:
+    type: postgres
+    show_sql: true
+    postgres:
+      hostname: analytics.internal
+`))
+	if err != nil {
+		t.Fatalf("LoadConfigFromBytes returned error: %v", err)
+	}
+
+	cfg := app.GetConfig()
+	if cfg == nil {
+		t.Fatal("expected config")
+	}
+	analytics, ok := cfg.Databases["analytics_db"]
+	if !ok {
+		t.Fatalf("expected databases.analytics_db to be loaded, got %v", cfg.Databases)
+	}
+	if analytics.Type != DatabasePostgres || !analytics.ShowSql || analytics.Postgres.Hostname != "analytics.internal" {
+		t.Fatalf("unexpected analytics database config: %+v", analytics)
+	}
+}
@@ -0,0 +1,224 @@
+package orz
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// configReloadDebounce 合并编辑器保存文件时产生的多次写入事件
+const configReloadDebounce = 100 * time.Millisecond
+
+// ConfigChangeFunc 配置变化回调，old 为重新加载前的配置
+type ConfigChangeFunc func(old, new *Config)
+
+type configWatch struct {
+	reloadMu    sync.Mutex
+	mu          sync.Mutex
+	subscribers []ConfigChangeFunc
+}
+
+// OnConfigChange 订阅配置变化，配置重新加载成功后按注册顺序调用
+func (a *App) OnConfigChange(fn ConfigChangeFunc) {
+	if fn == nil {
+		panic("orz: OnConfigChange called with nil func")
+	}
+
+	a.configWatch.mu.Lock()
+	defer a.configWatch.mu.Unlock()
+	a.configWatch.subscribers = append(a.configWatch.subscribers, fn)
+}
+
+// ReloadConfig 重新加载配置并通知订阅者
+// 日志级别等可安全变更的配置立即生效，监听地址、数据库等需要重启的变更只记录警告
+func (a *App) ReloadConfig() error {
+	a.configWatch.reloadMu.Lock()
+	defer a.configWatch.reloadMu.Unlock()
+
+	old, err := a.configManager.Decode()
+	if err != nil {
+		return err
+	}
+	if err := a.configManager.reload(a.validateConfig); err != nil {
+		return err
+	}
+	a.appConfigs.reset()
+	current, err := a.configManager.Decode()
+	if err != nil {
+		return err
+	}
+
+	a.applyConfigChange(old, current)
+
+	a.configWatch.mu.Lock()
+	subscribers := append([]ConfigChangeFunc(nil), a.configWatch.subscribers...)
+	a.configWatch.mu.Unlock()
+	for _, fn := range subscribers {
+		fn(old, current)
+	}
+
+	a.Logger().Info("config reloaded")
+	return nil
+}
+
+// applyConfigChange 应用可在运行时生效的配置变更
+func (a *App) applyConfigChange(old, current *Config) {
+	if a.logLevels != nil {
+		before := a.logLevels.snapshot()
+		a.logLevels.apply(current.Log.Level, current.Log.Levels)
+		if after := a.logLevels.snapshot(); !reflect.DeepEqual(before, after) {
+			a.Logger().Info("log level changed", zap.String("level", after.Level), zap.Any("levels", after.Levels))
+		}
+	}
+
+	if a.echo != nil && !reflect.DeepEqual(old.Server.AccessLog, current.Server.AccessLog) {
+		a.configureAccessLog(current.Server.AccessLog)
+		a.Logger().Info("access log config changed")
+	}
+
+	for _, key := range restartRequiredChanges(old, current) {
+		a.Logger().Warn("config change requires restart to take effect", zap.String("key", key))
+	}
+}
+
+// restartRequiredChanges 返回只能在重启后生效的已变更配置项
+func restartRequiredChanges(old, current *Config) []string {
+	var changed []string
+	check := func(key string, before, after any) {
+		if !reflect.DeepEqual(before, after) {
+			changed = append(changed, key)
+		}
+	}
+
+	oldLog, currentLog := old.Log, current.Log
+	oldLog.Level, currentLog.Level = "", ""
+	oldLog.Levels, currentLog.Levels = nil, nil
+
+	check("log", oldLog, currentLog)
+	check("server.addr", old.Server.Addr, current.Server.Addr)
+	check("server.ip_extractor", old.Server.IPExtractor, current.Server.IPExtractor)
+	check("server.ip_trust_list", old.Server.IPTrustList, current.Server.IPTrustList)
+	check("server.health", old.Server.Health, current.Server.Health)
+	check("server.admin", old.Server.Admin, current.Server.Admin)
+	check("database", old.Database, current.Database)
+	check("databases", old.Databases, current.Databases)
+	return changed
+}
+
+// watchConfig 配置从文件加载时注册配置监听任务
+// 始终响应 SIGHUP，config.watch 为 true 时还会监听配置文件与 conf.d 目录的变化
+func (a *App) watchConfig() {
+	if a.configManager.ConfigFile() == "" {
+		return
+	}
+
+	watch := false
+	if config := a.GetConfig(); config != nil {
+		watch = config.Source.Watch
+	}
+
+	a.Go("config-watcher", func(ctx context.Context) error {
+		return a.runConfigWatcher(ctx, watch)
+	})
+}
+
+func (a *App) runConfigWatcher(ctx context.Context, watch bool) error {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	dropInDir := filepath.Join(filepath.Dir(a.configManager.ConfigFile()), configDropInDir)
+
+	var (
+		events <-chan fsnotify.Event
+		errs   <-chan error
+	)
+	if watch {
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return fmt.Errorf("failed to create config watcher: %w", err)
+		}
+		defer watcher.Close()
+
+		// 监听所在目录而不是文件本身，以便处理编辑器先写临时文件再重命名的保存方式
+		dirs := []string{filepath.Dir(a.configManager.ConfigFile())}
+		if info, err := os.Stat(dropInDir); err == nil && info.IsDir() {
+			dirs = append(dirs, dropInDir)
+		}
+		for _, file := range a.configManager.DotEnvFiles() {
+			dir := filepath.Dir(file)
+			if info, err := os.Stat(dir); err == nil && info.IsDir() && !slices.Contains(dirs, dir) {
+				dirs = append(dirs, dir)
+			}
+		}
+		for _, dir := range dirs {
+			if err := watcher.Add(dir); err != nil {
+				return fmt.Errorf("failed to watch config directory %s: %w", dir, err)
+			}
+		}
+		events, errs = watcher.Events, watcher.Errors
+	}
+
+	reload := func(reason string) {
+		if err := a.ReloadConfig(); err != nil {
+			a.Logger().Error("failed to reload config", zap.String("reason", reason), zap.Error(err))
+		}
+	}
+
+	var pending <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-hup:
+			reload("SIGHUP")
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if a.isConfigFileEvent(event, dropInDir) {
+				pending = time.After(configReloadDebounce)
+			}
+		case err, ok := <-errs:
+			if !ok {
+				return nil
+			}
+			a.Logger().Warn("config watcher error", zap.Error(err))
+		case <-pending:
+			pending = nil
+			reload("file changed")
+		}
+	}
+}
+
+// isConfigFileEvent 判断文件事件是否涉及已加载的配置文件、dotenv 文件，或 conf.d 中的 YAML 文件
+func (a *App) isConfigFileEvent(event fsnotify.Event, dropInDir string) bool {
+	if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Remove) {
+		return false
+	}
+
+	name := filepath.Clean(event.Name)
+	if filepath.Dir(name) == filepath.Clean(dropInDir) {
+		ext := filepath.Ext(name)
+		return ext == ".yaml" || ext == ".yml"
+	}
+	if event.Has(fsnotify.Remove) {
+		return false
+	}
+	for _, file := range append(a.configManager.ConfigFiles(), a.configManager.DotEnvFiles()...) {
+		if filepath.Clean(file) == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package orz
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func writeConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+}
+
+func newReloadableApp(t *testing.T, content string) (*App, string, *observer.ObservedLogs) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, content)
+
+	app := NewApp()
+	if err := app.LoadConfigFromFile(path); err != nil {
+		t.Fatalf("LoadConfigFromFile returned error: %v", err)
+	}
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	app.logger = zap.New(core)
+	app.logLevels = newLogLevels(app.GetConfig().Log.Level, nil)
+	return app, path, logs
+}
+
+func TestReloadConfigNotifiesSubscribersAndAppliesLogLevel(t *testing.T) {
+	app, path, logs := newReloadableApp(t, "log:\n  level: info\nserver:\n  addr: \":8080\"\napp:\n  greeting: hello\n")
+
+	var old, current *Config
+	app.OnConfigChange(func(o, n *Config) {
+		old, current = o, n
+	})
+
+	writeConfigFile(t, path, "log:\n  level: debug\nserver:\n  addr: \":9090\"\napp:\n  greeting: hi\n")
+	if err := app.ReloadConfig(); err != nil {
+		t.Fatalf("ReloadConfig returned error: %v", err)
+	}
+
+	if old == nil || current == nil {
+		t.Fatal("expected subscriber to be notified")
+	}
+	if old.App["greeting"] != "hello" || current.App["greeting"] != "hi" {
+		t.Fatalf("unexpected app section change: %v -> %v", old.App, current.App)
+	}
+	if app.GetConfig().App["greeting"] != "hi" {
+		t.Fatal("expected GetConfig to return the reloaded app section")
+	}
+	if app.logLevels.Level() != zapcore.DebugLevel {
+		t.Fatalf("expected log level to change to debug, got %s", app.logLevels.Level())
+	}
+
+	warnings := logs.FilterMessage("config change requires restart to take effect").All()
+	if len(warnings) != 1 || warnings[0].ContextMap()["key"] != "server.addr" {
+		t.Fatalf("expected a restart warning for server.addr, got %v", warnings)
+	}
+}
+
+func TestReloadConfigKeepsCurrentConfigOnError(t *testing.T) {
+	app, path, _ := newReloadableApp(t, "app:\n  greeting: hello\n")
+
+	notified := false
+	app.OnConfigChange(func(old, new *Config) {
+		notified = true
+	})
+
+	writeConfigFile(t, path, "app: [unterminated\n")
+	if err := app.ReloadConfig(); err == nil {
+		t.Fatal("expected ReloadConfig to fail on invalid YAML")
+	}
+	if notified {
+		t.Fatal("expected subscribers not to be notified on failed reload")
+	}
+	if app.GetConfig().App["greeting"] != "hello" {
+		t.Fatal("expected previous config to be kept")
+	}
+}
+
+func TestReloadConfigReplaysAllSources(t *testing.T) {
+	app, path, _ := newReloadableApp(t, "app:\n  greeting: hello\n")
+	if err := app.LoadConfigFromMap(map[string]interface{}{"server": map[string]interface{}{"addr": ":7070"}}); err != nil {
+		t.Fatalf("LoadConfigFromMap returned error: %v", err)
+	}
+
+	writeConfigFile(t, path, "app:\n  greeting: hi\n")
+	if err := app.ReloadConfig(); err != nil {
+		t.Fatalf("ReloadConfig returned error: %v", err)
+	}
+
+	config := app.GetConfig()
+	if config.App["greeting"] != "hi" || config.Server.Addr != ":7070" {
+		t.Fatalf("expected file and map sources to be reapplied, got app=%v addr=%q", config.App, config.Server.Addr)
+	}
+}
+
+func TestConfigWatcherReloadsOnFileChange(t *testing.T) {
+	app, path, _ := newReloadableApp(t, "config:\n  watch: true\napp:\n  greeting: hello\n")
+
+	changed := make(chan *Config, 1)
+	app.OnConfigChange(func(old, new *Config) {
+		select {
+		case changed <- new:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- app.runConfigWatcher(ctx, app.GetConfig().Source.Watch)
+	}()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("runConfigWatcher returned error: %v", err)
+		}
+	}()
+
+	// 等待监听建立后再修改文件
+	time.Sleep(50 * time.Millisecond)
+	writeConfigFile(t, path, "config:\n  watch: true\napp:\n  greeting: hi\n")
+
+	select {
+	case config := <-changed:
+		if config.App["greeting"] != "hi" {
+			t.Fatalf("unexpected reloaded config: %v", config.App)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for config reload")
+	}
+}
@@ -0,0 +1,255 @@
+package orz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// provider 单个类型的构造器与懒加载的单例
+type provider struct {
+	construct func(app *App) (any, error)
+	instance  any
+	built     bool
+	building  *build // 正在构造时不为空
+}
+
+// build 一次进行中的构造，其它 goroutine 等待 done 关闭后重新检查结果
+type build struct {
+	owner uint64 // 执行构造的 goroutine
+	done  chan struct{}
+}
+
+// container 类型化依赖容器
+// 依赖在首次 Resolve 时构造，之后始终返回同一实例
+// 构造器内部通过 Resolve 获取其它依赖，应在 Configure 等启动阶段完成首次解析
+type container struct {
+	mu        sync.Mutex
+	providers map[reflect.Type]*provider
+	paths     map[uint64][]reflect.Type // 每个 goroutine 当前的解析路径，用于描述循环依赖
+	waits     map[uint64]*provider      // 每个 goroutine 正在等待的构造
+	closers   []io.Closer
+	hooked    bool
+}
+
+// Provide 注册类型 T 的构造器，同一类型重复注册会 panic
+// 构造出的实例如果实现了 io.Closer，会在应用停止时按构造顺序的逆序关闭
+func Provide[T any](app *App, constructor func(app *App) (T, error)) {
+	if constructor == nil {
+		panic("orz: provider constructor is nil")
+	}
+
+	key := reflect.TypeFor[T]()
+	c := &app.container
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.providers == nil {
+		c.providers = make(map[reflect.Type]*provider)
+	}
+	if _, exists := c.providers[key]; exists {
+		panic(fmt.Sprintf("orz: provider already registered for type %s", key))
+	}
+	c.providers[key] = &provider{
+		construct: func(app *App) (any, error) {
+			return constructor(app)
+		},
+	}
+}
+
+// Resolve 获取类型 T 的单例，首次调用时构造
+func Resolve[T any](app *App) (T, error) {
+	var zero T
+	instance, err := app.container.resolve(app, reflect.TypeFor[T]())
+	if err != nil {
+		return zero, err
+	}
+	// 构造器返回 nil 接口时断言失败，返回零值
+	value, _ := instance.(T)
+	return value, nil
+}
+
+// MustResolve 获取类型 T 的单例，失败时 panic
+func MustResolve[T any](app *App) T {
+	instance, err := Resolve[T](app)
+	if err != nil {
+		panic(err)
+	}
+	return instance
+}
+
+func (c *container) resolve(app *App, key reflect.Type) (any, error) {
+	c.mu.Lock()
+	p, ok := c.providers[key]
+	if !ok {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("no provider registered for type %s", key)
+	}
+	if p.built {
+		c.mu.Unlock()
+		return p.instance, nil
+	}
+
+	// 只有需要构造或等待时才区分 goroutine
+	id := goroutineID()
+	// 其它 goroutine 正在构造时等待其完成，构造失败则由当前 goroutine 重试
+	for !p.built && p.building != nil {
+		if c.waitsFor(p.building.owner, id) {
+			cycle := c.describeCycle(id, key)
+			c.mu.Unlock()
+			return nil, fmt.Errorf("dependency cycle detected: %s", cycle)
+		}
+		done := p.building.done
+		c.setWait(id, p)
+		c.mu.Unlock()
+		<-done
+		c.mu.Lock()
+		c.setWait(id, nil)
+	}
+	if p.built {
+		c.mu.Unlock()
+		return p.instance, nil
+	}
+	current := &build{owner: id, done: make(chan struct{})}
+	p.building = current
+	c.pushPath(id, key)
+	c.mu.Unlock()
+
+	finished := false
+	defer func() {
+		// 构造器 panic 时同样释放等待者
+		if !finished {
+			c.mu.Lock()
+			p.building = nil
+			c.popPath(id)
+			c.mu.Unlock()
+			close(current.done)
+		}
+	}()
+
+	instance, err := p.construct(app)
+	finished = true
+
+	c.mu.Lock()
+	defer close(current.done)
+	defer c.mu.Unlock()
+
+	p.building = nil
+	c.popPath(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct %s: %w", key, err)
+	}
+
+	p.instance = instance
+	p.built = true
+	if closer, ok := instance.(io.Closer); ok {
+		c.closers = append(c.closers, closer)
+		if !c.hooked {
+			c.hooked = true
+			app.OnStop("container", func(ctx context.Context) error {
+				return c.close()
+			})
+		}
+	}
+	return instance, nil
+}
+
+// waitsFor 判断 goroutine owner 是否直接或间接在等待 goroutine id，需在持有 mu 时调用
+// owner 与 id 相同时即为同一条解析路径上的循环
+func (c *container) waitsFor(owner, id uint64) bool {
+	for seen := 0; seen <= len(c.waits); seen++ {
+		if owner == id {
+			return true
+		}
+		p, ok := c.waits[owner]
+		if !ok || p.building == nil {
+			return false
+		}
+		owner = p.building.owner
+	}
+	return false
+}
+
+// setWait 记录 goroutine 正在等待的构造，p 为空时清除，需在持有 mu 时调用
+func (c *container) setWait(id uint64, p *provider) {
+	if p == nil {
+		delete(c.waits, id)
+		return
+	}
+	if c.waits == nil {
+		c.waits = make(map[uint64]*provider)
+	}
+	c.waits[id] = p
+}
+
+// pushPath 与 popPath 维护 goroutine 的解析路径，需在持有 mu 时调用
+func (c *container) pushPath(id uint64, key reflect.Type) {
+	if c.paths == nil {
+		c.paths = make(map[uint64][]reflect.Type)
+	}
+	c.paths[id] = append(c.paths[id], key)
+}
+
+func (c *container) popPath(id uint64) {
+	path := c.paths[id]
+	if len(path) <= 1 {
+		delete(c.paths, id)
+		return
+	}
+	c.paths[id] = path[:len(path)-1]
+}
+
+// describeCycle 需在持有 mu 时调用
+func (c *container) describeCycle(id uint64, key reflect.Type) string {
+	path := c.paths[id]
+	start := 0
+	for i, t := range path {
+		if t == key {
+			start = i
+			break
+		}
+	}
+
+	names := make([]string, 0, len(path)-start+1)
+	for _, t := range path[start:] {
+		names = append(names, t.String())
+	}
+	names = append(names, key.String())
+	return strings.Join(names, " -> ")
+}
+
+// goroutineID 返回当前 goroutine 的编号
+// 构造器通过 Resolve(app) 获取依赖，无法传递调用链，因此按 goroutine 区分解析路径
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	fields := strings.Fields(strings.TrimPrefix(string(buf[:n]), "goroutine "))
+	if len(fields) == 0 {
+		return 0
+	}
+	id, _ := strconv.ParseUint(fields[0], 10, 64)
+	return id
+}
+
+// close 按构造顺序的逆序关闭实现了 io.Closer 的依赖
+func (c *container) close() error {
+	c.mu.Lock()
+	closers := c.closers
+	c.closers = nil
+	c.mu.Unlock()
+
+	var errs []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i].Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close %T: %w", closers[i], err))
+		}
+	}
+	return errors.Join(errs...)
+}
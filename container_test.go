@@ -0,0 +1,157 @@
+package orz
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+type containerRepo struct {
+	closed *[]string
+}
+
+func (r *containerRepo) Close() error {
+	*r.closed = append(*r.closed, "repo")
+	return nil
+}
+
+type containerService struct {
+	repo   *containerRepo
+	closed *[]string
+}
+
+func (s *containerService) Close() error {
+	*s.closed = append(*s.closed, "service")
+	return nil
+}
+
+func TestResolveConstructsLazySingletons(t *testing.T) {
+	app := NewApp()
+	app.SetLogger(zap.NewNop())
+
+	var closed []string
+	constructed := 0
+	Provide(app, func(app *App) (*containerRepo, error) {
+		constructed++
+		return &containerRepo{closed: &closed}, nil
+	})
+	Provide(app, func(app *App) (*containerService, error) {
+		repo, err := Resolve[*containerRepo](app)
+		if err != nil {
+			return nil, err
+		}
+		return &containerService{repo: repo, closed: &closed}, nil
+	})
+
+	if constructed != 0 {
+		t.Fatal("expected providers to be lazy")
+	}
+
+	first := MustResolve[*containerService](app)
+	second := MustResolve[*containerService](app)
+	if first != second {
+		t.Fatal("expected Resolve to return the same instance")
+	}
+	if first.repo != MustResolve[*containerRepo](app) || constructed != 1 {
+		t.Fatalf("expected repository to be constructed once, got %d", constructed)
+	}
+
+	if err := app.runStopHooks(); err != nil {
+		t.Fatalf("runStopHooks returned error: %v", err)
+	}
+	if strings.Join(closed, ",") != "service,repo" {
+		t.Fatalf("expected closers in reverse construction order, got %v", closed)
+	}
+}
+
+type cycleA struct{}
+type cycleB struct{}
+
+func TestResolveDetectsDependencyCycle(t *testing.T) {
+	app := NewApp()
+	Provide(app, func(app *App) (*cycleA, error) {
+		_, err := Resolve[*cycleB](app)
+		return &cycleA{}, err
+	})
+	Provide(app, func(app *App) (*cycleB, error) {
+		_, err := Resolve[*cycleA](app)
+		return &cycleB{}, err
+	})
+
+	_, err := Resolve[*cycleA](app)
+	if err == nil {
+		t.Fatal("expected dependency cycle error")
+	}
+	if !strings.Contains(err.Error(), "dependency cycle detected: *orz.cycleA -> *orz.cycleB -> *orz.cycleA") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestResolveWithoutProvider(t *testing.T) {
+	app := NewApp()
+	if _, err := Resolve[context.Context](app); err == nil || !strings.Contains(err.Error(), "no provider registered") {
+		t.Fatalf("expected missing provider error, got %v", err)
+	}
+}
+
+type slowService struct{}
+
+func TestResolveConcurrentlyWaitsForConstruction(t *testing.T) {
+	app := NewApp()
+	var constructed atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	Provide(app, func(app *App) (*slowService, error) {
+		if constructed.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return &slowService{}, nil
+	})
+
+	const resolvers = 8
+	results := make(chan error, resolvers)
+	instances := make(chan *slowService, resolvers)
+	resolve := func() {
+		service, err := Resolve[*slowService](app)
+		instances <- service
+		results <- err
+	}
+	go resolve()
+	<-started
+	for range resolvers - 1 {
+		go resolve()
+	}
+	close(release)
+
+	first := <-instances
+	for range resolvers - 1 {
+		if instance := <-instances; instance != first {
+			t.Fatal("expected every resolver to get the same instance")
+		}
+	}
+	for range resolvers {
+		if err := <-results; err != nil {
+			t.Fatalf("expected concurrent resolution to succeed, got %v", err)
+		}
+	}
+	if constructed.Load() != 1 {
+		t.Fatalf("expected one construction, got %d", constructed.Load())
+	}
+}
+
+func TestResolveNilInterface(t *testing.T) {
+	app := NewApp()
+	Provide(app, func(app *App) (io.Reader, error) {
+		return nil, nil
+	})
+
+	reader, err := Resolve[io.Reader](app)
+	if err != nil || reader != nil {
+		t.Fatalf("expected nil reader without error, got %v, %v", reader, err)
+	}
+}
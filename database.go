@@ -12,6 +12,9 @@ import (
 	gormlogger "gorm.io/gorm/logger"
 )
 
+// DefaultDatabaseName 默认数据库连接名称，对应配置中的 database 块
+const DefaultDatabaseName = "default"
+
 // ConnectDatabase 连接数据库
 func ConnectDatabase(cfg DatabaseConfig) (*gorm.DB, error) {
 	return connectDatabaseWithGormLogger(cfg, nil)
@@ -65,7 +68,15 @@ func ConnectSqlite(url string, cfg SqliteConfig, logger gormlogger.Interface) (*
 }
 
 func connectDatabaseWithGormLogger(cfg DatabaseConfig, logger gormlogger.Interface) (*gorm.DB, error) {
-	return openDatabase(cfg, logger)
+	db, err := openDatabase(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := connectReplicas(db, cfg, logger); err != nil {
+		return nil, err
+	}
+	return db, nil
 }
 
 // GormLogger GORM 日志适配器
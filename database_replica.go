@@ -0,0 +1,133 @@
+package orz
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+const (
+	ReplicaPolicyRandom     = "random"      // 随机选择只读副本
+	ReplicaPolicyRoundRobin = "round_robin" // 轮询选择只读副本
+
+	replicaPluginName = "orz:replicas"
+
+	primaryContextKey contextKey = "primary"
+)
+
+// replicaSet 以 GORM 插件的形式挂载在主库上，所有派生的会话共享同一组副本
+type replicaSet struct {
+	replicas []*gorm.DB
+	policy   string
+	next     atomic.Uint64
+}
+
+func (r *replicaSet) Name() string {
+	return replicaPluginName
+}
+
+func (r *replicaSet) Initialize(*gorm.DB) error {
+	return nil
+}
+
+func (r *replicaSet) pick() *gorm.DB {
+	if len(r.replicas) == 1 {
+		return r.replicas[0]
+	}
+	if r.policy == ReplicaPolicyRoundRobin {
+		return r.replicas[(r.next.Add(1)-1)%uint64(len(r.replicas))]
+	}
+	return r.replicas[rand.IntN(len(r.replicas))]
+}
+
+// UseReplicas 为主库挂载只读副本，仓库的读操作会按策略路由到副本
+// policy 为空时使用 random
+func UseReplicas(primary *gorm.DB, policy string, replicas ...*gorm.DB) error {
+	if len(replicas) == 0 {
+		return nil
+	}
+
+	policy, err := normalizeReplicaPolicy(policy)
+	if err != nil {
+		return err
+	}
+	return primary.Use(&replicaSet{replicas: replicas, policy: policy})
+}
+
+// WithPrimary 强制当前上下文中的读操作使用主库，用于保证写后读一致性
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey, true)
+}
+
+func usePrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryContextKey).(bool)
+	return primary
+}
+
+// replicaFor 为读操作选择副本，没有挂载副本或 db 处于事务中时返回 nil
+func replicaFor(db *gorm.DB) *gorm.DB {
+	if db == nil || db.Config == nil {
+		return nil
+	}
+	// 在事务上创建的仓库需要读到事务内的写入
+	if _, ok := db.Statement.ConnPool.(gorm.TxCommitter); ok {
+		return nil
+	}
+	plugin, ok := db.Config.Plugins[replicaPluginName]
+	if !ok {
+		return nil
+	}
+	return plugin.(*replicaSet).pick()
+}
+
+func normalizeReplicaPolicy(policy string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(policy)) {
+	case "", ReplicaPolicyRandom:
+		return ReplicaPolicyRandom, nil
+	case ReplicaPolicyRoundRobin, "round-robin", "roundrobin":
+		return ReplicaPolicyRoundRobin, nil
+	default:
+		return "", fmt.Errorf("unknown replica policy %q", policy)
+	}
+}
+
+// connectReplicas 连接配置中的只读副本并挂载到主库
+// 副本未指定类型时沿用主库的类型，失败时关闭主库与已打开的副本
+func connectReplicas(primary *gorm.DB, cfg DatabaseConfig, logger gormlogger.Interface) error {
+	if len(cfg.Replicas) == 0 {
+		return nil
+	}
+
+	replicas := make([]*gorm.DB, 0, len(cfg.Replicas))
+	for i, replicaCfg := range cfg.Replicas {
+		if replicaCfg.Type == "" {
+			replicaCfg.Type = cfg.Type
+		}
+		replica, err := openDatabase(replicaCfg, logger)
+		if err != nil {
+			closeDatabases(append(replicas, primary)...)
+			return fmt.Errorf("failed to connect replica %d: %w", i, err)
+		}
+		replicas = append(replicas, replica)
+	}
+
+	if err := UseReplicas(primary, cfg.ReplicaPolicy, replicas...); err != nil {
+		closeDatabases(append(replicas, primary)...)
+		return err
+	}
+	return nil
+}
+
+// closeDatabases 关闭连接池，用于连接失败时释放已打开的连接
+func closeDatabases(dbs ...*gorm.DB) {
+	for _, db := range dbs {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	}
+}
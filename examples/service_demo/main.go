@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"time"
 
 	"github.com/go-orz/orz"
@@ -180,8 +181,15 @@ func (a *DemoApp) Configure(app *orz.App) error {
 		return err
 	}
 
-	// 创建用户服务
-	a.userService = NewUserService(db)
+	// 通过依赖容器注册并获取用户服务
+	orz.Provide(app, func(app *orz.App) (*UserService, error) {
+		return NewUserService(app.GetDatabase()), nil
+	})
+	userService, err := orz.Resolve[*UserService](app)
+	if err != nil {
+		return err
+	}
+	a.userService = userService
 
 	fmt.Println("=== Service 基类使用演示 ===")
 	fmt.Println()
@@ -276,6 +284,7 @@ func main() {
 
 	framework, err := orz.NewFramework(
 		orz.WithConfigMap(configMap),
+		orz.WithFlags(os.Args), // 例如 --server.addr=:9000 --log.level=debug
 		orz.WithLoggerFromConfig(),
 		orz.WithDatabase(),
 		orz.WithApplication(app),
@@ -284,7 +293,8 @@ func main() {
 		log.Fatal("框架初始化失败:", err)
 	}
 
-	if err := framework.Run(); err != nil {
+	// 不带参数时启动服务，也可以执行 migrate、config print、routes 等命令
+	if err := framework.Execute(os.Args); err != nil {
 		log.Fatal("运行失败:", err)
 	}
 }
@@ -47,6 +47,9 @@ func TestNewFrameworkInitializesDependenciesBeforeApplication(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewFramework returned error: %v", err)
 	}
+	if err := framework.Initialize(); err != nil {
+		t.Fatalf("Initialize returned error: %v", err)
+	}
 	if framework == nil {
 		t.Fatal("expected framework")
 	}
@@ -54,3 +57,43 @@ func TestNewFrameworkInitializesDependenciesBeforeApplication(t *testing.T) {
 		t.Fatalf("application was not initialized with all dependencies: %+v", application)
 	}
 }
+
+func TestNewFrameworkConnectsNamedDatabases(t *testing.T) {
+	withIsolatedDatabaseDrivers(t)
+	connected := map[string]*gorm.DB{}
+	RegisterDatabaseDriver(func(cfg DatabaseConfig, logger gormlogger.Interface) (*gorm.DB, error) {
+		db := &gorm.DB{}
+		connected[cfg.URL] = db
+		return db, nil
+	}, DatabaseType("stub"))
+
+	framework, err := NewFramework(
+		WithDatabase(),
+		WithConfigMap(map[string]interface{}{
+			"database": map[string]interface{}{
+				"type": "stub",
+				"url":  "primary",
+			},
+			"databases": map[string]interface{}{
+				"analytics": map[string]interface{}{
+					"type": "stub",
+					"url":  "analytics",
+				},
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewFramework returned error: %v", err)
+	}
+	if err := framework.Initialize(); err != nil {
+		t.Fatalf("Initialize returned error: %v", err)
+	}
+
+	app := framework.App()
+	if app.GetDatabase() != connected["primary"] || app.GetDatabaseNamed(DefaultDatabaseName) != connected["primary"] {
+		t.Fatal("expected default database to use the database block")
+	}
+	if app.GetDatabaseNamed("analytics") != connected["analytics"] {
+		t.Fatal("expected analytics database to be connected")
+	}
+}
@@ -0,0 +1,178 @@
+package orz
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	healthStatusOK          = "ok"
+	healthStatusUnavailable = "unavailable"
+	healthStatusDraining    = "draining"
+)
+
+// HealthChecker 就绪检查器
+type HealthChecker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// HealthReport 就绪检查结果
+type HealthReport struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+type healthCheckerFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func (c healthCheckerFunc) Name() string {
+	return c.name
+}
+
+func (c healthCheckerFunc) Check(ctx context.Context) error {
+	return c.fn(ctx)
+}
+
+// NewHealthChecker 使用函数创建就绪检查器
+func NewHealthChecker(name string, fn func(ctx context.Context) error) HealthChecker {
+	return healthCheckerFunc{name: name, fn: fn}
+}
+
+// NewDatabaseHealthChecker 创建数据库连通性检查器
+func NewDatabaseHealthChecker(name string, db *gorm.DB) HealthChecker {
+	return NewHealthChecker(name, func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+}
+
+// health 管理就绪检查器与停机状态
+type health struct {
+	mu       sync.RWMutex
+	checkers []HealthChecker
+	draining atomic.Bool
+}
+
+// AddHealthChecker 注册就绪检查器
+func (a *App) AddHealthChecker(checker HealthChecker) {
+	a.health.mu.Lock()
+	defer a.health.mu.Unlock()
+	a.health.checkers = append(a.health.checkers, checker)
+}
+
+// Ready 执行所有就绪检查器，停机开始后直接返回未就绪
+// 已配置数据库时会自动包含名为 database（具名连接为 database:<name>）的连通性检查
+func (a *App) Ready(ctx context.Context) HealthReport {
+	if a.health.draining.Load() {
+		return HealthReport{Status: healthStatusDraining}
+	}
+
+	a.health.mu.RLock()
+	checkers := append([]HealthChecker(nil), a.health.checkers...)
+	a.health.mu.RUnlock()
+	checkers = append(a.databaseHealthCheckers(), checkers...)
+
+	report := HealthReport{
+		Status: healthStatusOK,
+		Checks: make(map[string]string, len(checkers)),
+	}
+	results := make([]error, len(checkers))
+
+	var wg sync.WaitGroup
+	for i, checker := range checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runHealthCheck(ctx, checker)
+		}()
+	}
+	wg.Wait()
+
+	for i, checker := range checkers {
+		if err := results[i]; err != nil {
+			report.Status = healthStatusUnavailable
+			report.Checks[checker.Name()] = err.Error()
+			continue
+		}
+		report.Checks[checker.Name()] = healthStatusOK
+	}
+	return report
+}
+
+func (a *App) databaseHealthCheckers() []HealthChecker {
+	var checkers []HealthChecker
+	if db := a.GetDatabase(); db != nil {
+		checkers = append(checkers, NewDatabaseHealthChecker("database", db))
+	}
+
+	names := make([]string, 0, len(a.databases))
+	for name := range a.databases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		checkers = append(checkers, NewDatabaseHealthChecker("database:"+name, a.databases[name]))
+	}
+	return checkers
+}
+
+func runHealthCheck(ctx context.Context, checker HealthChecker) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return checker.Check(ctx)
+}
+
+// registerHealthRoutes 注册存活与就绪检查接口
+func (a *App) registerHealthRoutes(e *echo.Echo, cfg HealthConfig) {
+	if !cfg.Enabled {
+		return
+	}
+
+	if cfg.LivenessPath != "" {
+		e.GET(cfg.LivenessPath, func(c *echo.Context) error {
+			return c.JSON(http.StatusOK, HealthReport{Status: healthStatusOK})
+		})
+	}
+
+	if cfg.ReadinessPath != "" {
+		e.GET(cfg.ReadinessPath, func(c *echo.Context) error {
+			ctx := c.Request().Context()
+			if cfg.Timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+				defer cancel()
+			}
+
+			report := a.Ready(ctx)
+			if report.Status != healthStatusOK {
+				return c.JSON(http.StatusServiceUnavailable, report)
+			}
+			return c.JSON(http.StatusOK, report)
+		})
+	}
+}
+
+// beginDraining 标记为未就绪，并等待负载均衡摘除流量
+func (a *App) beginDraining(delay time.Duration) {
+	if a.health.draining.Swap(true) || delay <= 0 {
+		return
+	}
+	time.Sleep(delay)
+}
@@ -0,0 +1,118 @@
+package orz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestEnableHTTPRegistersHealthEndpoints(t *testing.T) {
+	app := NewApp()
+	app.SetLogger(zap.NewNop())
+	app.EnableHTTP()
+
+	for _, path := range []string{"/healthz", "/readyz"} {
+		rec := httptest.NewRecorder()
+		app.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected %s to return 200, got %d", path, rec.Code)
+		}
+	}
+}
+
+func TestReadinessAggregatesCheckers(t *testing.T) {
+	app := NewApp()
+	app.SetLogger(zap.NewNop())
+	app.EnableHTTP()
+	app.AddHealthChecker(NewHealthChecker("cache", func(ctx context.Context) error {
+		return nil
+	}))
+	app.AddHealthChecker(NewHealthChecker("broker", func(ctx context.Context) error {
+		return errors.New("not connected")
+	}))
+
+	rec := httptest.NewRecorder()
+	app.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d", rec.Code)
+	}
+
+	var report HealthReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if report.Checks["cache"] != "ok" || report.Checks["broker"] != "not connected" {
+		t.Fatalf("unexpected readiness report: %+v", report)
+	}
+}
+
+func TestReadinessFailsOnceDraining(t *testing.T) {
+	app := NewApp()
+	app.SetLogger(zap.NewNop())
+	app.EnableHTTP()
+	app.beginDraining(0)
+
+	rec := httptest.NewRecorder()
+	app.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503 while draining, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	app.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected liveness to stay 200 while draining, got %d", rec.Code)
+	}
+}
+
+func TestHealthEndpointsCanBeDisabled(t *testing.T) {
+	app := NewApp()
+	app.SetLogger(zap.NewNop())
+	if err := app.LoadConfigFromMap(map[string]interface{}{
+		"server": map[string]interface{}{
+			"health": map[string]interface{}{
+				"enabled": false,
+			},
+		},
+	}); err != nil {
+		t.Fatalf("LoadConfigFromMap returned error: %v", err)
+	}
+	app.EnableHTTP()
+
+	rec := httptest.NewRecorder()
+	app.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", rec.Code)
+	}
+}
+
+func TestHealthEndpointSkippedForEmptyPath(t *testing.T) {
+	app := NewApp()
+	app.SetLogger(zap.NewNop())
+	if err := app.LoadConfigFromMap(map[string]interface{}{
+		"server": map[string]interface{}{
+			"health": map[string]interface{}{
+				"liveness_path": "",
+			},
+		},
+	}); err != nil {
+		t.Fatalf("LoadConfigFromMap returned error: %v", err)
+	}
+	if err := app.ValidateConfig(); err != nil {
+		t.Fatalf("expected empty liveness_path to be valid, got %v", err)
+	}
+	app.EnableHTTP()
+
+	for path, status := range map[string]int{"/healthz": http.StatusNotFound, "/readyz": http.StatusOK} {
+		rec := httptest.NewRecorder()
+		app.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != status {
+			t.Fatalf("expected %s to return %d, got %d", path, status, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package orz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// defaultHookTimeout 生命周期钩子的默认超时时间
+const defaultHookTimeout = 15 * time.Second
+
+// HookFunc 生命周期钩子函数
+type HookFunc func(ctx context.Context) error
+
+// HookOption 生命周期钩子选项
+type HookOption func(*lifecycleHook)
+
+// WithHookTimeout 设置单个钩子的超时时间
+func WithHookTimeout(timeout time.Duration) HookOption {
+	return func(h *lifecycleHook) {
+		if timeout > 0 {
+			h.timeout = timeout
+		}
+	}
+}
+
+type lifecycleHook struct {
+	name    string
+	fn      HookFunc
+	timeout time.Duration
+}
+
+// lifecycle 管理应用的启动与停止钩子
+type lifecycle struct {
+	mu         sync.Mutex
+	startHooks []lifecycleHook
+	stopHooks  []lifecycleHook
+}
+
+// OnStart 注册启动钩子，按注册顺序在服务启动前执行
+// 任一钩子失败都会中止启动，已注册的停止钩子仍会执行，并由 Run 返回该错误
+func (a *App) OnStart(name string, fn HookFunc, options ...HookOption) {
+	a.lifecycle.mu.Lock()
+	defer a.lifecycle.mu.Unlock()
+	a.lifecycle.startHooks = append(a.lifecycle.startHooks, newLifecycleHook(name, fn, options))
+}
+
+// OnStop 注册停止钩子，按注册顺序的逆序在服务停止后执行
+// 所有停止钩子都会被执行，错误会被记录并汇总返回
+func (a *App) OnStop(name string, fn HookFunc, options ...HookOption) {
+	a.lifecycle.mu.Lock()
+	defer a.lifecycle.mu.Unlock()
+	a.lifecycle.stopHooks = append(a.lifecycle.stopHooks, newLifecycleHook(name, fn, options))
+}
+
+func newLifecycleHook(name string, fn HookFunc, options []HookOption) lifecycleHook {
+	if fn == nil {
+		panic("orz: lifecycle hook is nil")
+	}
+
+	hook := lifecycleHook{
+		name:    name,
+		fn:      fn,
+		timeout: defaultHookTimeout,
+	}
+	for _, option := range options {
+		option(&hook)
+	}
+	return hook
+}
+
+// runStartHooks 按注册顺序执行启动钩子，遇到错误立即返回
+func (a *App) runStartHooks() error {
+	a.lifecycle.mu.Lock()
+	hooks := append([]lifecycleHook(nil), a.lifecycle.startHooks...)
+	a.lifecycle.mu.Unlock()
+
+	for _, hook := range hooks {
+		a.Logger().Debug("running start hook", zap.String("hook", hook.name))
+		if err := runHook(a.ctx, hook); err != nil {
+			a.Logger().Error("start hook failed", zap.String("hook", hook.name), zap.Error(err))
+			return fmt.Errorf("start hook %q failed: %w", hook.name, err)
+		}
+	}
+	return nil
+}
+
+// runStopHooks 按注册顺序的逆序执行停止钩子，汇总所有错误
+func (a *App) runStopHooks() error {
+	a.lifecycle.mu.Lock()
+	hooks := append([]lifecycleHook(nil), a.lifecycle.stopHooks...)
+	a.lifecycle.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hook := hooks[i]
+		a.Logger().Debug("running stop hook", zap.String("hook", hook.name))
+		// 停止阶段应用上下文已取消，使用独立的上下文执行钩子
+		if err := runHook(context.Background(), hook); err != nil {
+			a.Logger().Error("stop hook failed", zap.String("hook", hook.name), zap.Error(err))
+			errs = append(errs, fmt.Errorf("stop hook %q failed: %w", hook.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runHook 在超时控制下执行单个钩子，钩子忽略上下文时同样按超时返回
+func runHook(parent context.Context, hook lifecycleHook) error {
+	ctx, cancel := context.WithTimeout(parent, hook.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- hook.fn(ctx)
+	}()
+
+	timer := time.NewTimer(hook.timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("timed out after %s", hook.timeout)
+	}
+}
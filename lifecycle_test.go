@@ -0,0 +1,100 @@
+package orz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestRunExecutesHooksInOrder(t *testing.T) {
+	app := NewApp()
+	app.SetLogger(zap.NewNop())
+
+	var mu sync.Mutex
+	var calls []string
+	record := func(name string) HookFunc {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	app.OnStart("first", record("start:first"))
+	app.OnStart("second", record("start:second"))
+	app.OnStart("cancel", func(ctx context.Context) error {
+		app.cancel()
+		return nil
+	})
+	app.OnStop("first", record("stop:first"))
+	app.OnStop("second", record("stop:second"))
+
+	if err := app.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	want := []string{"start:first", "start:second", "stop:second", "stop:first"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected hook order: %v", calls)
+	}
+}
+
+func TestRunStopsWhenStartHookFails(t *testing.T) {
+	app := NewApp()
+	app.SetLogger(zap.NewNop())
+
+	stopped := false
+	app.OnStart("broker", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+	app.OnStart("never", func(ctx context.Context) error {
+		t.Fatal("start hook after failure should not run")
+		return nil
+	})
+	app.OnStop("broker", func(ctx context.Context) error {
+		stopped = true
+		return nil
+	})
+
+	err := app.Run()
+	if err == nil || !strings.Contains(err.Error(), `start hook "broker" failed`) {
+		t.Fatalf("expected start hook error, got %v", err)
+	}
+	if !stopped {
+		t.Fatal("expected stop hooks to run after start failure")
+	}
+}
+
+func TestRunCollectsStopHookErrors(t *testing.T) {
+	app := NewApp()
+	app.SetLogger(zap.NewNop())
+
+	app.OnStart("cancel", func(ctx context.Context) error {
+		app.cancel()
+		return nil
+	})
+	app.OnStop("cache", func(ctx context.Context) error {
+		return errors.New("flush failed")
+	})
+	app.OnStop("slow", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}, WithHookTimeout(20*time.Millisecond))
+
+	err := app.Run()
+	if err == nil {
+		t.Fatal("expected stop hook errors")
+	}
+	if !strings.Contains(err.Error(), `stop hook "cache" failed: flush failed`) {
+		t.Fatalf("expected cache stop error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `stop hook "slow" failed: timed out`) {
+		t.Fatalf("expected slow stop timeout, got %v", err)
+	}
+}
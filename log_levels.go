@@ -0,0 +1,231 @@
+package orz
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/labstack/echo/v5"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// logLevelNames log.level 与 log.levels 接受的日志级别
+var logLevelNames = []string{"debug", "info", "warn", "warning", "error", "fatal", "panic"}
+
+// logLevels 默认日志级别与按组件名称设置的日志级别，均可在运行时调整
+// 组件名称对应 Logger().Named(...) 的名称，未单独设置的子日志器（例如 jobs.sync）沿用父名称（jobs）的级别
+type logLevels struct {
+	mu         sync.Mutex // 串行化修改，读取不加锁
+	base       zap.AtomicLevel
+	components atomic.Pointer[map[string]zap.AtomicLevel]
+}
+
+func newLogLevels(level string, components map[string]string) *logLevels {
+	l := &logLevels{base: zap.NewAtomicLevelAt(parseLogLevel(level))}
+	l.apply(level, components)
+	return l
+}
+
+// Level 返回默认日志级别
+func (l *logLevels) Level() zapcore.Level {
+	return l.base.Level()
+}
+
+// levelFor 返回日志器名称对应的级别，按名称逐级向上查找
+func (l *logLevels) levelFor(name string) zapcore.Level {
+	components := *l.components.Load()
+	if len(components) > 0 && name != "" {
+		name = strings.ToLower(name)
+		for {
+			if level, ok := components[name]; ok {
+				return level.Level()
+			}
+			i := strings.LastIndexByte(name, '.')
+			if i < 0 {
+				break
+			}
+			name = name[:i]
+		}
+	}
+	return l.base.Level()
+}
+
+// Enabled 任一组件允许该级别时返回 true，具体日志器是否输出由 levelRouterCore.Check 决定
+func (l *logLevels) Enabled(level zapcore.Level) bool {
+	if l.base.Enabled(level) {
+		return true
+	}
+	for _, component := range *l.components.Load() {
+		if component.Enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// set 设置组件的日志级别，component 为空时设置默认级别
+func (l *logLevels) set(component, level string) error {
+	parsed, err := parseLogLevelName(level)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	component = strings.ToLower(strings.TrimSpace(component))
+	if component == "" {
+		l.base.SetLevel(parsed)
+		return nil
+	}
+	current := *l.components.Load()
+	if atomicLevel, ok := current[component]; ok {
+		atomicLevel.SetLevel(parsed)
+		return nil
+	}
+	next := maps.Clone(current)
+	if next == nil {
+		next = make(map[string]zap.AtomicLevel)
+	}
+	next[component] = zap.NewAtomicLevelAt(parsed)
+	l.components.Store(&next)
+	return nil
+}
+
+// unset 移除组件的日志级别，之后沿用父名称或默认级别
+func (l *logLevels) unset(component string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	next := maps.Clone(*l.components.Load())
+	delete(next, strings.ToLower(strings.TrimSpace(component)))
+	l.components.Store(&next)
+}
+
+// apply 按配置重置所有日志级别，运行时通过 set 做的修改会被覆盖
+func (l *logLevels) apply(level string, components map[string]string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.base.SetLevel(parseLogLevel(level))
+	current := l.components.Load()
+	next := make(map[string]zap.AtomicLevel, len(components))
+	for name, value := range components {
+		name = strings.ToLower(name)
+		// 复用已有的 AtomicLevel，已创建的日志器无需重建
+		if current != nil {
+			if atomicLevel, ok := (*current)[name]; ok {
+				atomicLevel.SetLevel(parseLogLevel(value))
+				next[name] = atomicLevel
+				continue
+			}
+		}
+		next[name] = zap.NewAtomicLevelAt(parseLogLevel(value))
+	}
+	l.components.Store(&next)
+}
+
+// snapshot 返回当前的默认级别与组件级别
+func (l *logLevels) snapshot() LogLevels {
+	components := make(map[string]string)
+	for name, level := range *l.components.Load() {
+		components[name] = level.Level().String()
+	}
+	return LogLevels{Level: l.base.Level().String(), Levels: components}
+}
+
+// parseLogLevelName 严格解析日志级别名称，用于运行时修改
+func parseLogLevelName(level string) (zapcore.Level, error) {
+	for _, name := range logLevelNames {
+		if strings.EqualFold(strings.TrimSpace(level), name) {
+			return parseLogLevel(name), nil
+		}
+	}
+	return zapcore.InfoLevel, fmt.Errorf("unknown log level %q, expected one of %s", level, strings.Join(logLevelNames, ", "))
+}
+
+// levelRouterCore 按日志器名称选择级别的 Core，包装的 Core 本身不做级别过滤
+type levelRouterCore struct {
+	zapcore.Core
+	levels *logLevels
+}
+
+func newLevelRouterCore(core zapcore.Core, levels *logLevels) zapcore.Core {
+	return &levelRouterCore{Core: core, levels: levels}
+}
+
+func (c *levelRouterCore) Enabled(level zapcore.Level) bool {
+	return c.levels.Enabled(level)
+}
+
+func (c *levelRouterCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelRouterCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+func (c *levelRouterCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level < c.levels.levelFor(entry.LoggerName) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
+
+// LogLevels 当前生效的日志级别
+type LogLevels struct {
+	Level  string            `json:"level"`            // 默认日志级别
+	Levels map[string]string `json:"levels,omitempty"` // 按组件名称设置的日志级别
+}
+
+// LogLevels 返回当前的日志级别，日志器不是由配置创建时返回 false
+func (a *App) LogLevels() (LogLevels, bool) {
+	if a.logLevels == nil {
+		return LogLevels{}, false
+	}
+	return a.logLevels.snapshot(), true
+}
+
+// SetLogLevel 在运行时修改日志级别，component 为空时修改默认级别，level 为空时移除组件级别
+// 只对由配置创建的日志器生效；重新加载配置后以 log.level 与 log.levels 为准
+func (a *App) SetLogLevel(component, level string) error {
+	if a.logLevels == nil {
+		return fmt.Errorf("log levels are not configurable for a custom logger")
+	}
+	if level == "" && component != "" {
+		a.logLevels.unset(component)
+		return nil
+	}
+	return a.logLevels.set(component, level)
+}
+
+// registerAdminRoutes 注册管理接口
+func (a *App) registerAdminRoutes(e *echo.Echo, cfg AdminConfig) {
+	if !cfg.Enabled || cfg.LogLevelsPath == "" {
+		return
+	}
+
+	e.GET(cfg.LogLevelsPath, func(c *echo.Context) error {
+		levels, ok := a.LogLevels()
+		if !ok {
+			return NotFound(c, "log levels are not configurable for a custom logger")
+		}
+		return Ok(c, levels)
+	})
+	e.PUT(cfg.LogLevelsPath, func(c *echo.Context) error {
+		var request struct {
+			Component string `json:"component"`
+			Level     string `json:"level"`
+		}
+		if err := c.Bind(&request); err != nil {
+			return BadRequest(c, err.Error())
+		}
+		if err := a.SetLogLevel(request.Component, request.Level); err != nil {
+			return BadRequest(c, err.Error())
+		}
+		a.Logger().Info("log level changed", zap.String("component", request.Component), zap.String("level", request.Level))
+
+		levels, _ := a.LogLevels()
+		return Ok(c, levels)
+	})
+}
@@ -0,0 +1,110 @@
+package orz
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLevelRouterCoreHonorsComponentLevels(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	levels := newLogLevels("info", map[string]string{"gorm": "warn", "jobs": "debug"})
+	logger := zap.New(newLevelRouterCore(core, levels))
+	gorm := logger.Named("gorm")
+
+	logger.Debug("root debug")
+	logger.Info("root info")
+	logger.Named("jobs").Debug("jobs debug")
+	logger.Named("jobs").Named("sync").With(zap.String("job", "x")).Debug("jobs.sync debug")
+	gorm.Info("gorm info")
+	gorm.Warn("gorm warn")
+
+	var messages []string
+	for _, entry := range logs.TakeAll() {
+		messages = append(messages, entry.Message)
+	}
+	if strings.Join(messages, ",") != "root info,jobs debug,jobs.sync debug,gorm warn" {
+		t.Fatalf("unexpected messages %v", messages)
+	}
+
+	// 修改级别对已创建的日志器立即生效
+	app := NewApp()
+	app.logLevels = levels
+	if err := app.SetLogLevel("gorm", "debug"); err != nil {
+		t.Fatalf("SetLogLevel returned error: %v", err)
+	}
+	gorm.Debug("gorm debug")
+	if err := app.SetLogLevel("gorm", ""); err != nil {
+		t.Fatalf("SetLogLevel returned error: %v", err)
+	}
+	gorm.Debug("dropped")
+	if err := app.SetLogLevel("", "verbose"); err == nil {
+		t.Fatal("expected unknown level to be rejected")
+	}
+	if entries := logs.TakeAll(); len(entries) != 1 || entries[0].Message != "gorm debug" {
+		t.Fatalf("unexpected entries after runtime change: %v", entries)
+	}
+}
+
+func TestReloadConfigAppliesComponentLevels(t *testing.T) {
+	app, path, _ := newReloadableApp(t, "log:\n  level: info\n  levels:\n    gorm: warn\n")
+	if levels, _ := app.LogLevels(); levels.Levels["gorm"] != "" {
+		t.Fatalf("expected helper to start without component levels, got %v", levels)
+	}
+	if app.GetConfig().Log.Levels["gorm"] != "warn" {
+		t.Fatalf("expected log.levels to be decoded, got %v", app.GetConfig().Log.Levels)
+	}
+
+	writeConfigFile(t, path, "log:\n  level: warn\n  levels:\n    gorm: debug\n    my_jobs: error\n")
+	if err := app.ReloadConfig(); err != nil {
+		t.Fatalf("ReloadConfig returned error: %v", err)
+	}
+	levels, ok := app.LogLevels()
+	if !ok || levels.Level != "warn" || levels.Levels["gorm"] != "debug" || levels.Levels["my_jobs"] != "error" {
+		t.Fatalf("unexpected levels after reload: %v", levels)
+	}
+
+	writeConfigFile(t, path, "log:\n  levels:\n    gorm: loud\n")
+	if err := app.ReloadConfig(); err == nil || !strings.Contains(err.Error(), "log.levels.gorm: must be one of") {
+		t.Fatalf("expected invalid component level to be rejected, got %v", err)
+	}
+}
+
+func TestAdminLogLevelsEndpoint(t *testing.T) {
+	app, _, _ := newReloadableApp(t, "server:\n  admin:\n    enabled: true\n")
+	app.EnableHTTP()
+	e := app.GetEcho()
+
+	request := func(method, body string) (*httptest.ResponseRecorder, LogLevels) {
+		req := httptest.NewRequest(method, "/admin/log-levels", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		var levels LogLevels
+		_ = json.Unmarshal(rec.Body.Bytes(), &levels)
+		return rec, levels
+	}
+
+	rec, levels := request(http.MethodPut, `{"component":"gorm","level":"debug"}`)
+	if rec.Code != http.StatusOK || levels.Levels["gorm"] != "debug" {
+		t.Fatalf("unexpected response %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec, _ := request(http.MethodPut, `{"level":"loud"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected bad request for unknown level, got %d", rec.Code)
+	}
+	if rec, levels := request(http.MethodGet, ""); rec.Code != http.StatusOK || levels.Level != "info" || levels.Levels["gorm"] != "debug" {
+		t.Fatalf("unexpected GET response %d: %s", rec.Code, rec.Body.String())
+	}
+
+	app.SetLogger(zap.NewNop())
+	if rec, _ := request(http.MethodGet, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected not found for custom logger, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,111 @@
+package orz
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxRateLimitWindows 超过后清理已过期的计数，避免消息种类很多时占用过多内存
+const maxRateLimitWindows = 4096
+
+// rateLimitCore 限制同一级别、同一消息每秒写入的条数，超出的日志被丢弃
+// 限流结束后的第一条日志带有 dropped 字段，记录期间丢弃的条数
+type rateLimitCore struct {
+	zapcore.Core
+	limiter *messageLimiter
+}
+
+func newRateLimitCore(core zapcore.Core, perSecond int) zapcore.Core {
+	return &rateLimitCore{Core: core, limiter: &messageLimiter{
+		limit:   perSecond,
+		windows: make(map[messageKey]*messageWindow),
+		now:     time.Now,
+	}}
+}
+
+func (c *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rateLimitCore{Core: c.Core.With(fields), limiter: c.limiter}
+}
+
+func (c *rateLimitCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(entry.Level) || !c.limiter.allow(messageKey{entry.Level, entry.Message}) {
+		return checked
+	}
+	return checked.AddCore(entry, c)
+}
+
+func (c *rateLimitCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if dropped := c.limiter.takeDropped(messageKey{entry.Level, entry.Message}); dropped > 0 {
+		fields = append(fields[:len(fields):len(fields)], zap.Int("dropped", dropped))
+	}
+	return c.Core.Write(entry, fields)
+}
+
+type messageKey struct {
+	level   zapcore.Level
+	message string
+}
+
+// messageWindow 一条消息在当前秒内的计数
+type messageWindow struct {
+	second  int64
+	count   int
+	dropped int
+}
+
+type messageLimiter struct {
+	limit   int
+	mu      sync.Mutex
+	windows map[messageKey]*messageWindow
+	now     func() time.Time
+}
+
+func (l *messageLimiter) allow(key messageKey) bool {
+	second := l.now().Unix()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	window, ok := l.windows[key]
+	if !ok {
+		if len(l.windows) >= maxRateLimitWindows {
+			l.evict(second)
+		}
+		window = &messageWindow{second: second}
+		l.windows[key] = window
+	}
+	if window.second != second {
+		window.second, window.count = second, 0
+	}
+	window.count++
+	if window.count > l.limit {
+		window.dropped++
+		return false
+	}
+	return true
+}
+
+func (l *messageLimiter) takeDropped(key messageKey) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	window, ok := l.windows[key]
+	if !ok {
+		return 0
+	}
+	dropped := window.dropped
+	window.dropped = 0
+	return dropped
+}
+
+// evict 删除不在当前秒且没有未报告丢弃数的计数
+func (l *messageLimiter) evict(second int64) {
+	for key, window := range l.windows {
+		if window.second != second && window.dropped == 0 {
+			delete(l.windows, key)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package orz
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRateLimitCoreDropsRepeatedMessages(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	limited := newRateLimitCore(core, 2).(*rateLimitCore)
+	now := time.Unix(1000, 0)
+	limited.limiter.now = func() time.Time { return now }
+	logger := zap.New(limited)
+
+	for range 5 {
+		logger.Info("hot loop")
+	}
+	logger.Warn("hot loop")
+	logger.Info("other")
+	now = now.Add(time.Second)
+	logger.Info("hot loop")
+
+	var counts []int64
+	for _, entry := range logs.TakeAll() {
+		if entry.Message != "hot loop" || entry.Level != zapcore.InfoLevel {
+			continue
+		}
+		dropped, _ := entry.ContextMap()["dropped"].(int64)
+		counts = append(counts, dropped)
+	}
+	if len(counts) != 3 || counts[0] != 0 || counts[1] != 0 || counts[2] != 3 {
+		t.Fatalf("expected 2 entries then one reporting 3 dropped, got %v", counts)
+	}
+}
+
+func TestLogConfigSamplingValidation(t *testing.T) {
+	v := NewValidation()
+	LogConfig{Level: "info", Sampling: LogSamplingConfig{Initial: -1}, RateLimit: -1}.ValidateConfig(v)
+	err := v.Err()
+	for _, expected := range []string{"sampling.initial: must not be negative", "rate_limit: must not be negative"} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected error to contain %q, got %v", expected, err)
+		}
+	}
+}
+
+func TestEnableLoggerAppliesRateLimitAndSampling(t *testing.T) {
+	cases := []struct {
+		name     string
+		config   string
+		expected int
+	}{
+		{"rate limit", "  rate_limit: 2\n", 2},
+		{"sampling", "  sampling:\n    initial: 3\n    thereafter: 0\n", 3},
+		{"both", "  rate_limit: 2\n  sampling:\n    initial: 3\n", 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "app.log")
+			app, _, _ := newReloadableApp(t, "log:\n  filename: "+file+"\n  console: false\n"+c.config)
+			if err := app.EnableLogger(); err != nil {
+				t.Fatalf("EnableLogger returned error: %v", err)
+			}
+			defer app.logWriter.Close()
+
+			for range 100 {
+				app.Logger().Info("hot loop")
+			}
+			content, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("failed to read log file: %v", err)
+			}
+			if lines := strings.Count(string(content), "hot loop"); lines != c.expected {
+				t.Fatalf("expected %d lines, got %d:\n%s", c.expected, lines, content)
+			}
+		})
+	}
+}
+
+func TestSecretRedactionKeepsCustomSampler(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	app := NewApp()
+	logger := app.withSecretRedaction(zap.New(zapcore.NewSamplerWithOptions(core, time.Minute, 1, 0)))
+
+	for range 10 {
+		logger.Info("hot loop")
+	}
+	if logs.Len() != 1 {
+		t.Fatalf("expected the custom sampler to drop repeated entries, got %d", logs.Len())
+	}
+}
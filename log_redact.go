@@ -0,0 +1,98 @@
+package orz
+
+import (
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultRedactKeys log.redact_keys 的默认值
+var defaultRedactKeys = []string{"password", "token", "authorization", "secret"}
+
+// keyRedactingCore 按字段名脱敏：字段名包含任一关键字时替换整个值，
+// 字符串字段与消息中的 JSON 对应键的值同样被替换，例如 {"password":"x"}
+type keyRedactingCore struct {
+	zapcore.Core
+	keys []string
+	json *regexp.Regexp
+}
+
+func newKeyRedactingCore(core zapcore.Core, keys []string) zapcore.Core {
+	var normalized, patterns []string
+	for _, key := range keys {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			continue
+		}
+		normalized = append(normalized, key)
+		patterns = append(patterns, regexp.QuoteMeta(key))
+	}
+	if len(normalized) == 0 {
+		return core
+	}
+
+	// 匹配 "<包含关键字的键>": <字符串、数字或字面量>
+	json := regexp.MustCompile(`(?i)("[^"]*(?:` + strings.Join(patterns, "|") + `)[^"]*"\s*:\s*)("(?:[^"\\]|\\.)*"|[^,}\]\s]+)`)
+	return &keyRedactingCore{Core: core, keys: normalized, json: json}
+}
+
+func (c *keyRedactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &keyRedactingCore{Core: c.Core.With(c.redactFields(fields)), keys: c.keys, json: c.json}
+}
+
+func (c *keyRedactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *keyRedactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = c.redactJSON(entry.Message)
+	return c.Core.Write(entry, c.redactFields(fields))
+}
+
+func (c *keyRedactingCore) sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, candidate := range c.keys {
+		if strings.Contains(key, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *keyRedactingCore) redactJSON(s string) string {
+	if !strings.Contains(s, `"`) {
+		return s
+	}
+	return c.json.ReplaceAllString(s, `${1}"`+redactedValue+`"`)
+}
+
+func (c *keyRedactingCore) redactFields(fields []zapcore.Field) []zapcore.Field {
+	var result []zapcore.Field
+	for i, field := range fields {
+		redacted, changed := field, false
+		switch {
+		case field.Type == zapcore.NamespaceType || field.Type == zapcore.SkipType:
+		case c.sensitive(field.Key):
+			redacted, changed = zap.String(field.Key, redactedValue), true
+		case field.Type == zapcore.StringType:
+			redacted.String = c.redactJSON(field.String)
+			changed = redacted.String != field.String
+		}
+		if !changed {
+			continue
+		}
+		if result == nil {
+			result = append([]zapcore.Field(nil), fields...)
+		}
+		result[i] = redacted
+	}
+	if result == nil {
+		return fields
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package orz
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestKeyRedactingCoreMasksFieldsAndJSON(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(newKeyRedactingCore(core, defaultRedactKeys)).With(zap.String("api_token", "abc"))
+
+	logger.Info(`login {"user":"bob","Password":"hunter2"}`,
+		zap.String("Authorization", "Bearer xyz"),
+		zap.Int("secret_id", 42),
+		zap.String("body", `{"access_token": "t-1", "nested": {"client_secret": 7}, "name": "x"}`),
+		zap.String("user", "bob"),
+	)
+
+	entries := logs.TakeAll()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %v", entries)
+	}
+	entry := entries[0]
+	if entry.Message != `login {"user":"bob","Password":"******"}` {
+		t.Fatalf("unexpected message %q", entry.Message)
+	}
+	expected := map[string]any{
+		"api_token":     redactedValue,
+		"Authorization": redactedValue,
+		"secret_id":     redactedValue,
+		"body":          `{"access_token": "******", "nested": {"client_secret": "******"}, "name": "x"}`,
+		"user":          "bob",
+	}
+	fields := entry.ContextMap()
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Fatalf("expected %s=%v, got %v", key, value, fields)
+		}
+	}
+
+	if newKeyRedactingCore(core, []string{" "}) != core {
+		t.Fatal("expected empty keys to leave the core unchanged")
+	}
+}
+
+func TestEnableLoggerRedactsAndHonorsLevels(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+	app, _, _ := newReloadableApp(t, "log:\n  level: info\n  filename: "+file+"\n  console: false\n  levels:\n    gorm: warn\n")
+	if err := app.EnableLogger(); err != nil {
+		t.Fatalf("EnableLogger returned error: %v", err)
+	}
+
+	app.Logger().Named("gorm").Info("gorm info")
+	app.Logger().Info("user created", zap.String("password", "hunter2"))
+	_ = app.Logger().Sync()
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if strings.Contains(string(content), "gorm info") {
+		t.Fatalf("expected log.levels to apply to the file output, got %s", content)
+	}
+	if !strings.Contains(string(content), "user created") || strings.Contains(string(content), "hunter2") {
+		t.Fatalf("expected password to be redacted, got %s", content)
+	}
+}
@@ -0,0 +1,318 @@
+package orz
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// logRotateModes log.rotate 接受的滚动方式
+var logRotateModes = []string{"size", "daily", "hourly"}
+
+// rotatingWriter 可滚动的日志文件，lumberjack.Logger 与 timeRotatingWriter 均实现该接口
+type rotatingWriter interface {
+	io.WriteCloser
+	Rotate() error
+}
+
+// timeRotated log.rotate 是否按时间滚动
+func timeRotated(rotate string) bool {
+	return strings.EqualFold(rotate, "daily") || strings.EqualFold(rotate, "hourly")
+}
+
+// newLogFileWriter 按 log.rotate 创建日志文件写入器，未配置日志文件时返回 nil
+func newLogFileWriter(cfg LogConfig) rotatingWriter {
+	if timeRotated(cfg.Rotate) {
+		pattern := logFilenamePattern(cfg)
+		if pattern == "" {
+			return nil
+		}
+		return &timeRotatingWriter{
+			pattern:    pattern,
+			maxBackups: cfg.MaxBackups,
+			maxAge:     time.Duration(getMaxAge(cfg.MaxAge)) * 24 * time.Hour,
+			compress:   cfg.Compress,
+			now:        time.Now,
+		}
+	}
+	if cfg.Filename == "" {
+		return nil
+	}
+	return &lumberjack.Logger{
+		Filename:   cfg.Filename,
+		MaxSize:    getMaxSize(cfg.MaxSize),
+		MaxAge:     getMaxAge(cfg.MaxAge),
+		MaxBackups: cfg.MaxBackups,
+		Compress:   cfg.Compress,
+		LocalTime:  true,
+	}
+}
+
+// logFilename 返回当前写入的日志文件名，未配置日志文件时返回空字符串
+func logFilename(cfg LogConfig, now time.Time) string {
+	if timeRotated(cfg.Rotate) {
+		return expandLogPattern(logFilenamePattern(cfg), now)
+	}
+	return cfg.Filename
+}
+
+// logFilenamePattern 返回按时间滚动的文件名模式
+// 未配置 log.filename_pattern 时在 log.filename 的扩展名前加上日期，例如 logs/app.log 按天滚动写入 logs/app-2026-10-17.log
+func logFilenamePattern(cfg LogConfig) string {
+	if cfg.FilenamePattern != "" || cfg.Filename == "" {
+		return cfg.FilenamePattern
+	}
+	ext := filepath.Ext(cfg.Filename)
+	base := strings.TrimSuffix(cfg.Filename, ext)
+	if strings.EqualFold(cfg.Rotate, "hourly") {
+		return base + "-%Y-%m-%d-%H" + ext
+	}
+	return base + "-%Y-%m-%d" + ext
+}
+
+// expandLogPattern 替换文件名模式中的 %Y %m %d %H %M，%% 表示 % 本身
+func expandLogPattern(pattern string, t time.Time) string {
+	layouts := map[byte]string{'Y': "2006", 'm': "01", 'd': "02", 'H': "15", 'M': "04"}
+	return replaceLogPattern(pattern, func(token byte) string {
+		return t.Format(layouts[token])
+	}, func(c byte) string {
+		return string(c)
+	})
+}
+
+// logPatternGlob 将文件名模式转换为匹配所有滚动文件的 glob
+// 文字中的通配符替换为 ?，只匹配单个字符，避免处理各平台不同的转义方式
+func logPatternGlob(pattern string) string {
+	return replaceLogPattern(pattern, func(byte) string {
+		return "*"
+	}, func(c byte) string {
+		if c == '*' || c == '?' || c == '[' {
+			return "?"
+		}
+		return string(c)
+	})
+}
+
+func replaceLogPattern(pattern string, token func(byte) string, literal func(byte) string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '%' && i+1 < len(pattern) {
+			switch next := pattern[i+1]; next {
+			case 'Y', 'm', 'd', 'H', 'M':
+				b.WriteString(token(next))
+				i++
+				continue
+			case '%':
+				b.WriteString(literal('%'))
+				i++
+				continue
+			}
+		}
+		b.WriteString(literal(pattern[i]))
+	}
+	return b.String()
+}
+
+// timeRotatingWriter 按时间滚动的日志文件
+// 文件名由模式与当前时间决定，时间段变化后写入新文件，并在后台压缩与清理之前的文件
+type timeRotatingWriter struct {
+	pattern    string
+	maxBackups int
+	maxAge     time.Duration
+	compress   bool
+	now        func() time.Time
+
+	mu   sync.Mutex
+	file *os.File
+	name string
+
+	millMu sync.Mutex // 串行化压缩与清理
+	mills  sync.WaitGroup
+}
+
+func (w *timeRotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if name := expandLogPattern(w.pattern, w.now()); w.file == nil || name != w.name {
+		if err := w.open(name); err != nil {
+			return 0, err
+		}
+	}
+	return w.file.Write(p)
+}
+
+// Rotate 关闭并重新打开当前文件，外部 logrotate 移走文件后由此写入新文件
+func (w *timeRotatingWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.open(expandLogPattern(w.pattern, w.now()))
+}
+
+// Close 关闭当前文件，并等待进行中的压缩与清理结束
+func (w *timeRotatingWriter) Close() error {
+	w.mu.Lock()
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+	w.mu.Unlock()
+
+	w.mills.Wait()
+	return err
+}
+
+func (w *timeRotatingWriter) open(name string) error {
+	if w.file != nil {
+		_ = w.file.Close()
+		w.file = nil
+	}
+	if err := ensureDir(name); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	previous := w.name
+	w.file, w.name = file, name
+	if previous != name {
+		w.mills.Add(1)
+		go func() {
+			defer w.mills.Done()
+			w.mill(name)
+		}()
+	}
+	return nil
+}
+
+// mill 压缩之前的日志文件，并按 maxBackups 与 maxAge 删除过多、过旧的文件
+func (w *timeRotatingWriter) mill(current string) {
+	w.millMu.Lock()
+	defer w.millMu.Unlock()
+
+	glob := logPatternGlob(w.pattern)
+	plain, _ := filepath.Glob(glob)
+	compressed, _ := filepath.Glob(glob + ".gz")
+
+	type backup struct {
+		name    string
+		modTime time.Time
+	}
+	var backups []backup
+	for _, name := range append(plain, compressed...) {
+		if name == current {
+			continue
+		}
+		info, err := os.Stat(name)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		backups = append(backups, backup{name: name, modTime: info.ModTime()})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].modTime.After(backups[j].modTime)
+	})
+
+	cutoff := w.now().Add(-w.maxAge)
+	for i, file := range backups {
+		if (w.maxBackups > 0 && i >= w.maxBackups) || (w.maxAge > 0 && file.modTime.Before(cutoff)) {
+			_ = os.Remove(file.name)
+			continue
+		}
+		if w.compress && !strings.HasSuffix(file.name, ".gz") {
+			// 与 lumberjack 一致，压缩失败时保留原文件
+			_ = compressLogFile(file.name)
+		}
+	}
+}
+
+// compressLogFile 将日志文件压缩为 .gz 并删除原文件，保留修改时间以便按时间清理
+func compressLogFile(name string) (err error) {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(name+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode())
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err == nil {
+		err = gz.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(name + ".gz")
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	_ = os.Chtimes(name+".gz", info.ModTime(), info.ModTime())
+	return os.Remove(name)
+}
+
+// RotateLogs 滚动应用日志文件与访问日志文件
+// 按大小滚动时将当前文件改名为备份文件，按时间滚动时重新打开当前文件，配合外部 logrotate 使用
+func (a *App) RotateLogs() error {
+	var errs []error
+	if a.logWriter != nil {
+		if err := a.logWriter.Rotate(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to rotate log file: %w", err))
+		}
+	}
+	if current := a.accessLog.current.Load(); current != nil && current.writer != nil {
+		if err := current.writer.Rotate(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to rotate access log file: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// watchLogRotation 配置了日志文件时注册任务，收到 logRotateSignals 中的信号后滚动日志
+func (a *App) watchLogRotation() {
+	config := a.GetConfig()
+	if len(logRotateSignals) == 0 || config == nil ||
+		(config.Log.Filename == "" && config.Log.FilenamePattern == "" && config.Server.AccessLog.Filename == "") {
+		return
+	}
+
+	a.Go("log-rotation", func(ctx context.Context) error {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, logRotateSignals...)
+		defer signal.Stop(signals)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case sig := <-signals:
+				if err := a.RotateLogs(); err != nil {
+					a.Logger().Error("failed to rotate logs", zap.String("signal", sig.String()), zap.Error(err))
+				}
+			}
+		}
+	})
+}
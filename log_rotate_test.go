@@ -0,0 +1,138 @@
+package orz
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogFilenamePattern(t *testing.T) {
+	at := time.Date(2026, 10, 17, 9, 5, 0, 0, time.Local)
+	cases := []struct {
+		config   LogConfig
+		expected string
+	}{
+		{LogConfig{Filename: "logs/app.log", Rotate: "size"}, "logs/app.log"},
+		{LogConfig{Filename: "logs/app.log", Rotate: "daily"}, "logs/app-2026-10-17.log"},
+		{LogConfig{Filename: "logs/app.log", Rotate: "hourly"}, "logs/app-2026-10-17-09.log"},
+		{LogConfig{Filename: "logs/app.log", Rotate: "daily", FilenamePattern: "logs/%Y/%m/app-%d_%H%M-100%%.log"}, "logs/2026/10/app-17_0905-100%.log"},
+	}
+	for _, c := range cases {
+		if actual := logFilename(c.config, at); actual != c.expected {
+			t.Fatalf("expected %q for %+v, got %q", c.expected, c.config, actual)
+		}
+	}
+	if glob := logPatternGlob("logs/app[1]-%Y-%m-%d.log"); glob != "logs/app?1]-*-*-*.log" {
+		t.Fatalf("unexpected glob %q", glob)
+	}
+}
+
+func TestTimeRotatingWriterRotatesAndCleansUp(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.Local)
+	w := &timeRotatingWriter{
+		pattern:    filepath.Join(dir, "logs", "app-%Y-%m-%d.log"),
+		maxBackups: 1,
+		maxAge:     7 * 24 * time.Hour,
+		compress:   true,
+		now:        func() time.Time { return now },
+	}
+	defer w.Close()
+
+	write := func(line string) string {
+		t.Helper()
+		if _, err := w.Write([]byte(line + "\n")); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+		w.mills.Wait()
+		// 固定修改时间，使清理顺序不依赖文件系统的时间精度
+		if err := os.Chtimes(w.name, now, now); err != nil {
+			t.Fatalf("Chtimes returned error: %v", err)
+		}
+		return w.name
+	}
+
+	first := write("day 1")
+	write("day 1 again")
+	now = now.AddDate(0, 0, 1)
+	second := write("day 2")
+	if _, err := os.Stat(first + ".gz"); err != nil {
+		t.Fatalf("expected previous day to be compressed: %v", err)
+	}
+	if _, err := os.Stat(first); !os.IsNotExist(err) {
+		t.Fatalf("expected uncompressed file to be removed, got %v", err)
+	}
+
+	now = now.AddDate(0, 0, 1)
+	third := write("day 3")
+	if _, err := os.Stat(first + ".gz"); !os.IsNotExist(err) {
+		t.Fatalf("expected backups beyond max_backups to be removed, got %v", err)
+	}
+	if _, err := os.Stat(second + ".gz"); err != nil {
+		t.Fatalf("expected latest backup to be kept: %v", err)
+	}
+	if content, _ := os.ReadFile(third); string(content) != "day 3\n" {
+		t.Fatalf("unexpected current file content %q", content)
+	}
+
+	// 外部 logrotate 移走文件后，Rotate 重新创建当前文件
+	if err := os.Rename(third, third+".1"); err != nil {
+		t.Fatalf("Rename returned error: %v", err)
+	}
+	if err := w.Rotate(); err != nil {
+		t.Fatalf("Rotate returned error: %v", err)
+	}
+	write("after rotate")
+	if content, _ := os.ReadFile(third); string(content) != "after rotate\n" {
+		t.Fatalf("expected a new current file after Rotate, got %q", content)
+	}
+}
+
+func TestEnableLoggerCreatesDirAndRotatesLogs(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "logs", "app.log")
+	app, _, _ := newReloadableApp(t, "log:\n  filename: "+file+"\n  console: false\n  max_backups: 2\n")
+	if err := app.EnableLogger(); err != nil {
+		t.Fatalf("EnableLogger returned error: %v", err)
+	}
+	defer app.logWriter.Close()
+	if info, err := os.Stat(filepath.Dir(file)); err != nil || !info.IsDir() {
+		t.Fatalf("expected log directory to be created, got %v", err)
+	}
+
+	app.Logger().Info("before rotate")
+	if err := app.RotateLogs(); err != nil {
+		t.Fatalf("RotateLogs returned error: %v", err)
+	}
+	app.Logger().Info("after rotate")
+
+	content, err := os.ReadFile(file)
+	if err != nil || strings.Contains(string(content), "before rotate") || !strings.Contains(string(content), "after rotate") {
+		t.Fatalf("expected a fresh log file after rotation, got %q (%v)", content, err)
+	}
+	backups, _ := filepath.Glob(filepath.Join(filepath.Dir(file), "app-*.log"))
+	if len(backups) != 1 {
+		t.Fatalf("expected one backup file, got %v", backups)
+	}
+}
+
+func TestLogConfigRotateValidation(t *testing.T) {
+	cases := []struct {
+		config   LogConfig
+		expected string
+	}{
+		{LogConfig{Rotate: "weekly"}, "rotate: must be one of size, daily, hourly"},
+		{LogConfig{MaxBackups: -1}, "max_backups: must not be negative"},
+		{LogConfig{Rotate: "size", FilenamePattern: "app-%Y-%m-%d.log"}, "filename_pattern: requires rotate to be daily or hourly"},
+		{LogConfig{Rotate: "daily", FilenamePattern: "app-%Y-%m.log"}, "filename_pattern: must contain %d"},
+		{LogConfig{Rotate: "hourly", FilenamePattern: "app-%Y-%m-%d.log"}, "filename_pattern: must contain %H"},
+	}
+	for _, c := range cases {
+		v := NewValidation()
+		c.config.ValidateConfig(v)
+		if err := v.Err(); err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Fatalf("expected error to contain %q, got %v", c.expected, err)
+		}
+	}
+}
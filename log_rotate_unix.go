@@ -0,0 +1,11 @@
+//go:build !windows
+
+package orz
+
+import (
+	"os"
+	"syscall"
+)
+
+// logRotateSignals 触发日志滚动的信号
+var logRotateSignals = []os.Signal{syscall.SIGUSR1}
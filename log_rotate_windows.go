@@ -0,0 +1,8 @@
+//go:build windows
+
+package orz
+
+import "os"
+
+// logRotateSignals Windows 没有 SIGUSR1，只能调用 App.RotateLogs 滚动日志
+var logRotateSignals []os.Signal
@@ -4,15 +4,22 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 func NewLoggerFromConfig(cfg LogConfig) *zap.Logger {
-	// 解析日志级别
-	level := parseLogLevel(cfg.Level)
+	logger, _, _ := newLoggerFromConfig(cfg)
+	return logger
+}
+
+// newLoggerFromConfig 创建日志器，同时返回可在运行时调整的日志级别与日志文件，未配置日志文件时后者为 nil
+func newLoggerFromConfig(cfg LogConfig) (*zap.Logger, *logLevels, rotatingWriter) {
+	// 级别由 levelRouterCore 按日志器名称判断，各输出 Core 不再过滤
+	levels := newLogLevels(cfg.Level, cfg.Levels)
+	level := zapcore.DebugLevel
 
 	// 基础 encoder 配置（无颜色，给文件用）
 	baseEncoderConfig := zap.NewProductionEncoderConfig()
@@ -30,15 +37,8 @@ func NewLoggerFromConfig(cfg LogConfig) *zap.Logger {
 	var cores []zapcore.Core
 
 	// 文件输出（无颜色）
-	if cfg.Filename != "" {
-		rotateWriter := &lumberjack.Logger{
-			Filename:  cfg.Filename,
-			MaxSize:   getMaxSize(cfg.MaxSize),
-			MaxAge:    getMaxAge(cfg.MaxAge),
-			Compress:  cfg.Compress,
-			LocalTime: true,
-		}
-
+	writer := newLogFileWriter(cfg)
+	if writer != nil {
 		var fileEncoder zapcore.Encoder
 		if strings.ToLower(cfg.Encode) == "json" {
 			fileEncoder = zapcore.NewJSONEncoder(baseEncoderConfig)
@@ -46,7 +46,7 @@ func NewLoggerFromConfig(cfg LogConfig) *zap.Logger {
 			fileEncoder = zapcore.NewConsoleEncoder(baseEncoderConfig)
 		}
 
-		fileCore := zapcore.NewCore(fileEncoder, zapcore.AddSync(rotateWriter), level)
+		fileCore := zapcore.NewCore(fileEncoder, zapcore.AddSync(writer), level)
 		cores = append(cores, fileCore)
 	}
 
@@ -57,10 +57,17 @@ func NewLoggerFromConfig(cfg LogConfig) *zap.Logger {
 		cores = append(cores, consoleCore)
 	}
 
-	// 合并 core
-	core := zapcore.NewTee(cores...)
+	// 合并 core，依次经过级别判断、采样、限流，最后脱敏后写入所有输出
+	core := newKeyRedactingCore(zapcore.NewTee(cores...), cfg.RedactKeys)
+	if cfg.RateLimit > 0 {
+		core = newRateLimitCore(core, cfg.RateLimit)
+	}
+	if cfg.Sampling.Initial > 0 {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, cfg.Sampling.Initial, cfg.Sampling.Thereafter)
+	}
+	core = newLevelRouterCore(core, levels)
 	logger := zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
-	return logger
+	return logger, levels, writer
 }
 
 // parseLogLevel 解析日志级别
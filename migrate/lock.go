@@ -0,0 +1,88 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+)
+
+// lock 获取跨实例的迁移锁，返回释放函数
+// MySQL 使用 GET_LOCK，PostgreSQL 使用 advisory lock，两者都是会话级锁，
+// 因此在独立的连接上持有锁直到迁移结束。其它数据库（如 SQLite）不支持跨进程锁，不加锁。
+func (m *Migrator) lock(ctx context.Context) (func() error, error) {
+	dialect := m.db.Dialector.Name()
+	if dialect != "mysql" && dialect != "postgres" {
+		return func() error { return nil }, nil
+	}
+
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var unlock func() error
+	switch dialect {
+	case "mysql":
+		unlock, err = m.lockMysql(ctx, conn)
+	case "postgres":
+		unlock, err = m.lockPostgres(ctx, conn)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return func() error {
+		defer conn.Close()
+		if err := unlock(); err != nil {
+			return fmt.Errorf("failed to release migration lock: %w", err)
+		}
+		return nil
+	}, nil
+}
+
+func (m *Migrator) lockMysql(ctx context.Context, conn *sql.Conn) (func() error, error) {
+	var acquired sql.NullInt64
+	err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", m.lockName, int(m.lockTimeout.Seconds())).Scan(&acquired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if !acquired.Valid || acquired.Int64 != 1 {
+		return nil, fmt.Errorf("timed out waiting for migration lock %q after %s", m.lockName, m.lockTimeout)
+	}
+
+	return func() error {
+		_, err := conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", m.lockName)
+		return err
+	}, nil
+}
+
+func (m *Migrator) lockPostgres(ctx context.Context, conn *sql.Conn) (func() error, error) {
+	key := advisoryLockKey(m.lockName)
+
+	lockCtx, cancel := context.WithTimeout(ctx, m.lockTimeout)
+	defer cancel()
+	if _, err := conn.ExecContext(lockCtx, "SELECT pg_advisory_lock($1)", key); err != nil {
+		if lockCtx.Err() != nil {
+			return nil, fmt.Errorf("timed out waiting for migration lock %q after %s", m.lockName, m.lockTimeout)
+		}
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	return func() error {
+		_, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+		return err
+	}, nil
+}
+
+// advisoryLockKey 将锁名称映射为 PostgreSQL advisory lock 使用的整数键
+func advisoryLockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,286 @@
+// Package migrate 提供版本化的数据库迁移
+//
+// 迁移可以是 Go 函数，也可以是通过 embed.FS 嵌入的 SQL 文件，
+// 已执行的版本记录在跟踪表中，执行期间通过数据库锁避免多个实例并发迁移。
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultTable       = "schema_migrations"
+	defaultLockName    = "orz_schema_migrations"
+	defaultLockTimeout = time.Minute
+)
+
+// Migration 单个版本化迁移
+type Migration struct {
+	Version int64  // 版本号，按升序执行，例如 1、2 或 20260101120000
+	Name    string // 描述名称
+	Up      func(ctx context.Context, tx *gorm.DB) error
+	Down    func(ctx context.Context, tx *gorm.DB) error
+
+	// NoTransaction 为 true 时不在事务中执行，
+	// 用于 PostgreSQL CREATE INDEX CONCURRENTLY 等不能在事务中执行的语句
+	NoTransaction bool
+}
+
+// Status 迁移执行状态
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// record 跟踪表中的一行
+type record struct {
+	Version   int64  `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"size:255"`
+	AppliedAt time.Time
+}
+
+// Option 迁移器选项
+type Option func(*Migrator)
+
+// WithTable 设置跟踪表名，默认 schema_migrations
+func WithTable(table string) Option {
+	return func(m *Migrator) {
+		if table != "" {
+			m.table = table
+		}
+	}
+}
+
+// WithLockName 设置迁移锁名称，共享同一数据库的不同服务应使用不同的名称
+func WithLockName(name string) Option {
+	return func(m *Migrator) {
+		if name != "" {
+			m.lockName = name
+		}
+	}
+}
+
+// WithLockTimeout 设置等待迁移锁的最长时间，默认 1 分钟
+func WithLockTimeout(timeout time.Duration) Option {
+	return func(m *Migrator) {
+		if timeout > 0 {
+			m.lockTimeout = timeout
+		}
+	}
+}
+
+// Migrator 迁移执行器
+type Migrator struct {
+	db          *gorm.DB
+	table       string
+	lockName    string
+	lockTimeout time.Duration
+	migrations  map[int64]Migration
+}
+
+// New 创建迁移执行器
+func New(db *gorm.DB, options ...Option) *Migrator {
+	m := &Migrator{
+		db:          db,
+		table:       defaultTable,
+		lockName:    defaultLockName,
+		lockTimeout: defaultLockTimeout,
+		migrations:  make(map[int64]Migration),
+	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+// Add 注册 Go 函数形式的迁移，版本号不能重复
+func (m *Migrator) Add(migrations ...Migration) error {
+	for _, migration := range migrations {
+		if migration.Version <= 0 {
+			return fmt.Errorf("migration %q has invalid version %d", migration.Name, migration.Version)
+		}
+		if migration.Up == nil {
+			return fmt.Errorf("migration %d has no up step", migration.Version)
+		}
+		if existing, ok := m.migrations[migration.Version]; ok {
+			return fmt.Errorf("duplicate migration version %d: %q and %q", migration.Version, existing.Name, migration.Name)
+		}
+		m.migrations[migration.Version] = migration
+	}
+	return nil
+}
+
+// Migrations 返回按版本升序排列的所有迁移
+func (m *Migrator) Migrations() []Migration {
+	migrations := make([]Migration, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations
+}
+
+// Up 执行所有未执行的迁移，返回本次执行的迁移
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func() error {
+		records, err := m.appliedRecords(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.Migrations() {
+			if _, ok := records[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, migration); err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down 按版本倒序回滚最近执行的 n 个迁移，返回本次回滚的迁移
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("down steps must be positive, got %d", n)
+	}
+
+	var reverted []Migration
+	err := m.withLock(ctx, func() error {
+		records, err := m.appliedRecords(ctx)
+		if err != nil {
+			return err
+		}
+
+		versions := make([]int64, 0, len(records))
+		for version := range records {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i] > versions[j]
+		})
+
+		for _, version := range versions[:min(n, len(versions))] {
+			migration, ok := m.migrations[version]
+			if !ok {
+				return fmt.Errorf("applied migration %d is not registered", version)
+			}
+			if err := m.revert(ctx, migration); err != nil {
+				return err
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status 返回所有已注册迁移的执行状态，以及跟踪表中存在但未注册的版本
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	records, err := m.appliedRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.Migrations() {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if r, ok := records[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = r.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	for version, r := range records {
+		if _, ok := m.migrations[version]; !ok {
+			statuses = append(statuses, Status{Version: version, Name: r.Name, Applied: true, AppliedAt: r.AppliedAt})
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+func (m *Migrator) appliedRecords(ctx context.Context) (map[int64]record, error) {
+	if err := m.db.WithContext(ctx).Table(m.table).AutoMigrate(&record{}); err != nil {
+		return nil, fmt.Errorf("failed to create migration table %s: %w", m.table, err)
+	}
+
+	var records []record
+	if err := m.db.WithContext(ctx).Table(m.table).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read migration table %s: %w", m.table, err)
+	}
+
+	applied := make(map[int64]record, len(records))
+	for _, r := range records {
+		applied[r.Version] = r
+	}
+	return applied, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, migration Migration) error {
+	err := m.run(ctx, migration, func(tx *gorm.DB) error {
+		if err := migration.Up(ctx, tx); err != nil {
+			return err
+		}
+		return tx.Table(m.table).Create(&record{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+func (m *Migrator) revert(ctx context.Context, migration Migration) error {
+	if migration.Down == nil {
+		return fmt.Errorf("migration %d (%s) has no down step", migration.Version, migration.Name)
+	}
+
+	err := m.run(ctx, migration, func(tx *gorm.DB) error {
+		if err := migration.Down(ctx, tx); err != nil {
+			return err
+		}
+		return tx.Table(m.table).Where("version = ?", migration.Version).Delete(&record{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("rollback of migration %d (%s) failed: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+func (m *Migrator) run(ctx context.Context, migration Migration, fn func(tx *gorm.DB) error) error {
+	db := m.db.WithContext(ctx)
+	if migration.NoTransaction {
+		return fn(db)
+	}
+	return db.Transaction(fn)
+}
+
+// withLock 在迁移锁保护下执行
+func (m *Migrator) withLock(ctx context.Context, fn func() error) error {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+	return errors.Join(fn(), unlock())
+}
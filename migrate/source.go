@@ -0,0 +1,111 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// noTransactionDirective 写在 SQL 文件第一行时，该迁移不在事务中执行
+const noTransactionDirective = "-- orz:no-transaction"
+
+// sqlFilePattern 匹配 <version>_<name>.up.sql 与 <version>_<name>.down.sql
+var sqlFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+type sqlMigration struct {
+	name    string
+	up      string
+	down    string
+	hasUp   bool
+	hasDown bool
+}
+
+// AddFS 从文件系统加载 SQL 迁移，通常传入 embed.FS
+// 文件名格式为 <version>_<name>.up.sql / <version>_<name>.down.sql，会递归查找所有子目录。
+// 每个文件作为一次 Exec 执行，MySQL 下包含多条语句时需要在 DSN 中开启 multiStatements。
+func (m *Migrator) AddFS(fsys fs.FS) error {
+	files := make(map[int64]*sqlMigration)
+
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		matches := sqlFilePattern.FindStringSubmatch(path.Base(p))
+		if matches == nil {
+			return nil
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid migration version in %s: %w", p, err)
+		}
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+
+		file, ok := files[version]
+		if !ok {
+			file = &sqlMigration{name: matches[2]}
+			files[version] = file
+		} else if file.name != matches[2] {
+			return fmt.Errorf("migration version %d has conflicting names %q and %q", version, file.name, matches[2])
+		}
+
+		switch matches[3] {
+		case "up":
+			if file.hasUp {
+				return fmt.Errorf("duplicate up migration for version %d", version)
+			}
+			file.up, file.hasUp = string(content), true
+		case "down":
+			if file.hasDown {
+				return fmt.Errorf("duplicate down migration for version %d", version)
+			}
+			file.down, file.hasDown = string(content), true
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	for version, file := range files {
+		if !file.hasUp {
+			return fmt.Errorf("migration %d (%s) has no up file", version, file.name)
+		}
+
+		migration := Migration{
+			Version:       version,
+			Name:          file.name,
+			Up:            execSQL(file.up),
+			NoTransaction: strings.HasPrefix(strings.TrimSpace(file.up), noTransactionDirective),
+		}
+		if file.hasDown {
+			migration.Down = execSQL(file.down)
+		}
+		if err := m.Add(migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func execSQL(statement string) func(ctx context.Context, tx *gorm.DB) error {
+	return func(ctx context.Context, tx *gorm.DB) error {
+		if strings.TrimSpace(statement) == "" {
+			return nil
+		}
+		return tx.Exec(statement).Error
+	}
+}
@@ -0,0 +1,72 @@
+package migrate
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"gorm.io/gorm"
+)
+
+func TestAddFSLoadsVersionedSQLFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/0002_add_email.up.sql":      {Data: []byte("ALTER TABLE users ADD COLUMN email TEXT;")},
+		"migrations/0001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY);")},
+		"migrations/0001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		"migrations/0003_index.up.sql":          {Data: []byte("-- orz:no-transaction\nCREATE INDEX CONCURRENTLY idx ON users (email);")},
+		"migrations/README.md":                  {Data: []byte("ignored")},
+	}
+
+	m := New(nil)
+	if err := m.AddFS(fsys); err != nil {
+		t.Fatalf("AddFS returned error: %v", err)
+	}
+	if err := m.Add(Migration{Version: 4, Name: "backfill", Up: func(ctx context.Context, tx *gorm.DB) error { return nil }}); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+
+	migrations := m.Migrations()
+	if len(migrations) != 4 {
+		t.Fatalf("expected 4 migrations, got %d", len(migrations))
+	}
+	for i, want := range []string{"create_users", "add_email", "index", "backfill"} {
+		if migrations[i].Version != int64(i+1) || migrations[i].Name != want {
+			t.Fatalf("unexpected migration at %d: %d %s", i, migrations[i].Version, migrations[i].Name)
+		}
+	}
+	if migrations[0].Down == nil || migrations[1].Down != nil {
+		t.Fatal("expected down step only for migrations with a down file")
+	}
+	if !migrations[2].NoTransaction || migrations[0].NoTransaction {
+		t.Fatal("expected no-transaction directive to be honored")
+	}
+}
+
+func TestAddFSRejectsDuplicateVersions(t *testing.T) {
+	m := New(nil)
+	err := m.AddFS(fstest.MapFS{
+		"0001_create_users.up.sql":  {Data: []byte("SELECT 1;")},
+		"0001_create_orders.up.sql": {Data: []byte("SELECT 1;")},
+	})
+	if err == nil || !strings.Contains(err.Error(), "conflicting names") {
+		t.Fatalf("expected conflicting version error, got %v", err)
+	}
+
+	m = New(nil)
+	if err := m.AddFS(fstest.MapFS{"0001_create_users.up.sql": {Data: []byte("SELECT 1;")}}); err != nil {
+		t.Fatalf("AddFS returned error: %v", err)
+	}
+	err = m.Add(Migration{Version: 1, Name: "seed", Up: func(ctx context.Context, tx *gorm.DB) error { return nil }})
+	if err == nil || !strings.Contains(err.Error(), "duplicate migration version 1") {
+		t.Fatalf("expected duplicate version error, got %v", err)
+	}
+}
+
+func TestAddFSRequiresUpFile(t *testing.T) {
+	m := New(nil)
+	err := m.AddFS(fstest.MapFS{"0001_create_users.down.sql": {Data: []byte("DROP TABLE users;")}})
+	if err == nil || !strings.Contains(err.Error(), "has no up file") {
+		t.Fatalf("expected missing up file error, got %v", err)
+	}
+}
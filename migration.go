@@ -0,0 +1,71 @@
+package orz
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/go-orz/orz/migrate"
+	"go.uber.org/zap"
+)
+
+// WithMigrations 注册版本化迁移，框架初始化时在配置应用之前执行所有未执行的迁移
+// fsys 中的 SQL 文件按 migrate.Migrator.AddFS 的规则加载，可以为 nil 只使用 Go 迁移
+func WithMigrations(fsys fs.FS, migrations ...migrate.Migration) Option {
+	return func(f *Framework) error {
+		f.migrationSources = append(f.migrationSources, migrationSource{
+			fsys:       fsys,
+			migrations: migrations,
+		})
+		return nil
+	}
+}
+
+type migrationSource struct {
+	fsys       fs.FS
+	migrations []migrate.Migration
+}
+
+// newMigrator 使用默认数据库和已注册的迁移创建迁移执行器
+func (f *Framework) newMigrator() (*migrate.Migrator, error) {
+	db := f.app.GetDatabase()
+	if db == nil {
+		return nil, fmt.Errorf("migrations require a database, use WithDatabase")
+	}
+
+	migrator := migrate.New(db)
+	for _, source := range f.migrationSources {
+		if source.fsys != nil {
+			if err := migrator.AddFS(source.fsys); err != nil {
+				return nil, err
+			}
+		}
+		if err := migrator.Add(source.migrations...); err != nil {
+			return nil, err
+		}
+	}
+	return migrator, nil
+}
+
+// runMigrations 执行所有未执行的迁移
+func (f *Framework) runMigrations() error {
+	if len(f.migrationSources) == 0 {
+		return nil
+	}
+
+	migrator, err := f.newMigrator()
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up(f.app.Context())
+	for _, migration := range applied {
+		f.app.Logger().Info("migration applied",
+			zap.Int64("version", migration.Version),
+			zap.String("name", migration.Name),
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package orz
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+const (
+	SchemaCheckOff  = "off"  // 不检查
+	SchemaCheckWarn = "warn" // 发现差异时记录警告
+	SchemaCheckFail = "fail" // 发现差异时启动失败
+)
+
+// modelRegistry 按数据库名称记录注册的模型
+type modelRegistry struct {
+	mu     sync.Mutex
+	models map[string][]any
+}
+
+// RegisterModels 向默认数据库注册模型
+// 配置 database.auto_migrate 时启动阶段自动建表，配置 database.schema_check 时检查模型与数据库结构是否一致
+func (a *App) RegisterModels(models ...any) {
+	a.RegisterModelsNamed(DefaultDatabaseName, models...)
+}
+
+// RegisterModelsNamed 向具名数据库注册模型，使用 databases.<name> 中的 auto_migrate 与 schema_check 配置
+func (a *App) RegisterModelsNamed(name string, models ...any) {
+	if name == "" {
+		name = DefaultDatabaseName
+	}
+	for _, model := range models {
+		if model == nil {
+			panic("orz: RegisterModels called with nil model")
+		}
+	}
+
+	a.models.mu.Lock()
+	defer a.models.mu.Unlock()
+	if a.models.models == nil {
+		a.models.models = make(map[string][]any)
+	}
+	a.models.models[name] = append(a.models.models[name], models...)
+}
+
+// Models 返回注册到指定数据库的模型，name 为空时为默认数据库
+func (a *App) Models(name string) []any {
+	if name == "" {
+		name = DefaultDatabaseName
+	}
+
+	a.models.mu.Lock()
+	defer a.models.mu.Unlock()
+	return append([]any(nil), a.models.models[name]...)
+}
+
+// prepareModels 按配置对注册的模型执行 AutoMigrate 或结构检查
+func (f *Framework) prepareModels() error {
+	f.app.models.mu.Lock()
+	names := make([]string, 0, len(f.app.models.models))
+	for name := range f.app.models.models {
+		names = append(names, name)
+	}
+	f.app.models.mu.Unlock()
+	sort.Strings(names)
+
+	config := f.app.GetConfig()
+	if config == nil {
+		return nil
+	}
+
+	for _, name := range names {
+		cfg := config.Database
+		if name != DefaultDatabaseName {
+			cfg = config.Databases[name]
+		}
+		if err := f.prepareModelsFor(name, cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *Framework) prepareModelsFor(name string, cfg DatabaseConfig) error {
+	mode, err := normalizeSchemaCheck(cfg.SchemaCheck)
+	if err != nil {
+		return err
+	}
+	if !cfg.AutoMigrate && mode == SchemaCheckOff {
+		return nil
+	}
+
+	db := f.app.GetDatabaseNamed(name)
+	if db == nil {
+		return fmt.Errorf("models registered for database %q but it is not connected", name)
+	}
+	models := f.app.Models(name)
+	log := f.app.Logger().With(zap.String("database", name))
+
+	if cfg.AutoMigrate {
+		if err := db.WithContext(f.app.Context()).AutoMigrate(models...); err != nil {
+			return fmt.Errorf("failed to auto migrate models for database %q: %w", name, err)
+		}
+		log.Info("models auto migrated", zap.Int("count", len(models)))
+		return nil
+	}
+
+	issues, err := CheckSchema(db.WithContext(f.app.Context()), models...)
+	if err != nil {
+		return fmt.Errorf("failed to check schema for database %q: %w", name, err)
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+
+	if mode == SchemaCheckFail {
+		lines := make([]string, len(issues))
+		for i, issue := range issues {
+			lines[i] = issue.String()
+		}
+		return fmt.Errorf("schema check failed for database %q:\n  %s", name, strings.Join(lines, "\n  "))
+	}
+	for _, issue := range issues {
+		log.Warn("schema drift detected",
+			zap.String("table", issue.Table),
+			zap.String("column", issue.Column),
+			zap.String("issue", issue.Message),
+		)
+	}
+	return nil
+}
+
+func normalizeSchemaCheck(mode string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", SchemaCheckOff:
+		return SchemaCheckOff, nil
+	case SchemaCheckWarn:
+		return SchemaCheckWarn, nil
+	case SchemaCheckFail:
+		return SchemaCheckFail, nil
+	default:
+		return "", fmt.Errorf("unknown schema check mode %q", mode)
+	}
+}
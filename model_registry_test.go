@@ -0,0 +1,77 @@
+package orz
+
+import "testing"
+
+func TestBaseColumnType(t *testing.T) {
+	cases := map[string]string{
+		"varchar(191) NOT NULL":           "varchar",
+		"bigint AUTO_INCREMENT":           "bigint",
+		"INTEGER":                         "integer",
+		"timestamp with time zone":        "timestamp with time zone",
+		"double precision DEFAULT 0":      "double precision",
+		"decimal(10,2) unsigned":          "decimal",
+		"character varying(255) NOT NULL": "character varying",
+		"":                                "",
+	}
+	for definition, expected := range cases {
+		if got := baseColumnType(definition); got != expected {
+			t.Fatalf("baseColumnType(%q) = %q, want %q", definition, got, expected)
+		}
+	}
+}
+
+func TestCompatibleColumnTypes(t *testing.T) {
+	compatible := [][2]string{
+		{"bigint", "int8"},
+		{"varchar", "text"},
+		{"boolean", "tinyint"},
+		{"numeric", "bool"},
+		{"datetime", "timestamptz"},
+		{"geometry", "text"},
+	}
+	for _, pair := range compatible {
+		if !compatibleColumnTypes(pair[0], pair[1]) {
+			t.Fatalf("expected %s and %s to be compatible", pair[0], pair[1])
+		}
+	}
+
+	incompatible := [][2]string{
+		{"bigint", "text"},
+		{"datetime", "integer"},
+		{"varchar", "blob"},
+	}
+	for _, pair := range incompatible {
+		if compatibleColumnTypes(pair[0], pair[1]) {
+			t.Fatalf("expected %s and %s to be incompatible", pair[0], pair[1])
+		}
+	}
+}
+
+func TestNormalizeSchemaCheck(t *testing.T) {
+	for input, expected := range map[string]string{"": SchemaCheckOff, "OFF": SchemaCheckOff, "warn": SchemaCheckWarn, " fail ": SchemaCheckFail} {
+		got, err := normalizeSchemaCheck(input)
+		if err != nil || got != expected {
+			t.Fatalf("normalizeSchemaCheck(%q) = %q, %v; want %q", input, got, err, expected)
+		}
+	}
+	if _, err := normalizeSchemaCheck("strict"); err == nil {
+		t.Fatal("expected unknown schema check mode to be rejected")
+	}
+}
+
+func TestRegisterModelsGroupsByDatabase(t *testing.T) {
+	type user struct{ ID uint }
+	type event struct{ ID uint }
+
+	app := NewApp()
+	app.RegisterModels(&user{})
+	app.RegisterModelsNamed("", &user{})
+	app.RegisterModelsNamed("analytics", &event{})
+
+	if got := len(app.Models(DefaultDatabaseName)); got != 2 {
+		t.Fatalf("expected 2 default models, got %d", got)
+	}
+	if got := len(app.Models("analytics")); got != 1 {
+		t.Fatalf("expected 1 analytics model, got %d", got)
+	}
+}
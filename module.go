@@ -0,0 +1,129 @@
+package orz
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Module 可组合的功能模块，例如鉴权、审计、任务调度
+// 模块按依赖关系排序后依次配置，被依赖的模块总是先于依赖方配置
+type Module interface {
+	Name() string
+	Dependencies() []string
+	Configure(app *App) error
+}
+
+// ModuleStarter 需要在启动阶段执行逻辑的模块
+// Start 注册为启动钩子，按模块排序后的顺序执行
+type ModuleStarter interface {
+	Start(ctx context.Context) error
+}
+
+// ModuleStopper 需要在停止阶段执行逻辑的模块
+// Stop 注册为停止钩子，按模块排序后的逆序执行
+type ModuleStopper interface {
+	Stop(ctx context.Context) error
+}
+
+// WithModules 注册功能模块，可多次调用
+func WithModules(modules ...Module) Option {
+	return func(f *Framework) error {
+		for _, module := range modules {
+			if module == nil {
+				return fmt.Errorf("module is nil")
+			}
+		}
+		f.modules = append(f.modules, modules...)
+		return nil
+	}
+}
+
+// configureModules 按依赖顺序配置模块并注册其生命周期钩子
+func configureModules(app *App, modules []Module) error {
+	sorted, err := sortModules(modules)
+	if err != nil {
+		return err
+	}
+
+	for _, module := range sorted {
+		name := module.Name()
+		if err := module.Configure(app); err != nil {
+			return fmt.Errorf("failed to configure module %q: %w", name, err)
+		}
+		if starter, ok := module.(ModuleStarter); ok {
+			app.OnStart("module:"+name, starter.Start)
+		}
+		if stopper, ok := module.(ModuleStopper); ok {
+			app.OnStop("module:"+name, stopper.Stop)
+		}
+	}
+	return nil
+}
+
+// sortModules 对模块做拓扑排序，无依赖关系的模块保持注册顺序
+func sortModules(modules []Module) ([]Module, error) {
+	byName := make(map[string]Module, len(modules))
+	for _, module := range modules {
+		name := module.Name()
+		if name == "" {
+			return nil, fmt.Errorf("module name is empty: %T", module)
+		}
+		if _, exists := byName[name]; exists {
+			return nil, fmt.Errorf("module %q registered more than once", name)
+		}
+		byName[name] = module
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(modules))
+	sorted := make([]Module, 0, len(modules))
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for i, n := range path {
+				if n == name {
+					start = i
+					break
+				}
+			}
+			cycle := append(append([]string(nil), path[start:]...), name)
+			return fmt.Errorf("module dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+
+		module := byName[name]
+		for _, dependency := range module.Dependencies() {
+			if _, ok := byName[dependency]; !ok {
+				return fmt.Errorf("module %q depends on unknown module %q", name, dependency)
+			}
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[name] = visited
+		sorted = append(sorted, module)
+		return nil
+	}
+
+	for _, module := range modules {
+		if err := visit(module.Name()); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
@@ -0,0 +1,108 @@
+package orz
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type testModule struct {
+	name         string
+	dependencies []string
+	configured   *[]string
+}
+
+func (m *testModule) Name() string {
+	return m.name
+}
+
+func (m *testModule) Dependencies() []string {
+	return m.dependencies
+}
+
+func (m *testModule) Configure(app *App) error {
+	*m.configured = append(*m.configured, m.name)
+	return nil
+}
+
+type lifecycleModule struct {
+	testModule
+}
+
+func (m *lifecycleModule) Start(ctx context.Context) error {
+	return nil
+}
+
+func (m *lifecycleModule) Stop(ctx context.Context) error {
+	return nil
+}
+
+func TestWithModulesConfiguresInDependencyOrder(t *testing.T) {
+	var configured []string
+	framework, err := NewFramework(
+		WithModules(
+			&testModule{name: "jobs", dependencies: []string{"audit"}, configured: &configured},
+			&testModule{name: "audit", dependencies: []string{"auth"}, configured: &configured},
+			&testModule{name: "metrics", configured: &configured},
+			&testModule{name: "auth", configured: &configured},
+		),
+	)
+	if err != nil {
+		t.Fatalf("NewFramework returned error: %v", err)
+	}
+	if err := framework.Initialize(); err != nil {
+		t.Fatalf("Initialize returned error: %v", err)
+	}
+
+	if got := strings.Join(configured, ","); got != "auth,audit,jobs,metrics" {
+		t.Fatalf("unexpected module order: %s", got)
+	}
+}
+
+func TestWithModulesRegistersLifecycleHooks(t *testing.T) {
+	var configured []string
+	framework, err := NewFramework(
+		WithModules(&lifecycleModule{testModule{name: "jobs", configured: &configured}}),
+	)
+	if err != nil {
+		t.Fatalf("NewFramework returned error: %v", err)
+	}
+	if err := framework.Initialize(); err != nil {
+		t.Fatalf("Initialize returned error: %v", err)
+	}
+
+	lc := &framework.App().lifecycle
+	if len(lc.startHooks) != 1 || lc.startHooks[0].name != "module:jobs" {
+		t.Fatalf("expected module start hook, got %+v", lc.startHooks)
+	}
+	if len(lc.stopHooks) != 1 || lc.stopHooks[0].name != "module:jobs" {
+		t.Fatalf("expected module stop hook, got %+v", lc.stopHooks)
+	}
+}
+
+func TestWithModulesRejectsMissingDependency(t *testing.T) {
+	var configured []string
+	_, err := NewFramework(
+		WithModules(&testModule{name: "audit", dependencies: []string{"auth"}, configured: &configured}),
+	)
+	if err == nil || !strings.Contains(err.Error(), `module "audit" depends on unknown module "auth"`) {
+		t.Fatalf("expected missing dependency error, got %v", err)
+	}
+}
+
+func TestWithModulesRejectsCycle(t *testing.T) {
+	var configured []string
+	_, err := NewFramework(
+		WithModules(
+			&testModule{name: "a", dependencies: []string{"b"}, configured: &configured},
+			&testModule{name: "b", dependencies: []string{"c"}, configured: &configured},
+			&testModule{name: "c", dependencies: []string{"a"}, configured: &configured},
+		),
+	)
+	if err == nil || !strings.Contains(err.Error(), "module dependency cycle: a -> b -> c -> a") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+	if len(configured) != 0 {
+		t.Fatalf("expected no module to be configured, got %v", configured)
+	}
+}
@@ -2,8 +2,10 @@ package orz
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/labstack/echo/v5"
+	"github.com/spf13/pflag"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -13,15 +15,36 @@ type Map = map[string]any
 // Framework 框架结构
 type Framework struct {
 	app            *App
-	configLoaders  []func(*App) error
+	configLoaders  []configLoader
 	loggerMode     loggerMode
 	customLogger   *zap.Logger
 	enableDatabase bool
 	enableHTTP     bool
 	application    Application
 	applicationSet bool
+	modules        []Module
+
+	migrationSources []migrationSource
+
+	flagArgs []string       // WithFlags 传入的命令行参数，nil 表示不解析命令行参数
+	flags    *pflag.FlagSet // 解析后的配置参数
+	flagHelp bool           // 命令行参数包含 --help
+
+	stdin  io.Reader // 命令输入，默认 os.Stdin
+	stdout io.Writer // 命令输出，默认 os.Stdout
+
+	stage initStage // 已完成的初始化阶段
 }
 
+// initStage 初始化阶段，命令只执行所需的阶段
+type initStage uint8
+
+const (
+	stageConfig   initStage = iota + 1 // 加载并校验配置
+	stageDatabase                      // 初始化日志器并连接数据库
+	stageReady                         // 执行迁移，初始化 HTTP、模块与应用
+)
+
 type loggerMode uint8
 
 const (
@@ -36,25 +59,35 @@ type Option func(*Framework) error
 // WithConfig 设置配置文件路径
 func WithConfig(configPath string) Option {
 	return func(f *Framework) error {
-		f.configLoaders = append(f.configLoaders, func(app *App) error {
-			if err := app.LoadConfigFromFile(configPath); err != nil {
-				return fmt.Errorf("load config failed: %w", err)
-			}
-			return nil
-		})
+		f.configLoaders = append(f.configLoaders, configFileLoader(configPath))
 		return nil
 	}
 }
 
+// configLoader 一次配置加载，file 非空时为 WithConfig 加载的配置文件
+type configLoader struct {
+	file string
+	load func(*App) error
+}
+
+func configFileLoader(configPath string) configLoader {
+	return configLoader{file: configPath, load: func(app *App) error {
+		if err := app.LoadConfigFromFile(configPath); err != nil {
+			return fmt.Errorf("load config failed: %w", err)
+		}
+		return nil
+	}}
+}
+
 // WithConfigBytes 从字节数据设置配置
 func WithConfigBytes(data []byte) Option {
 	return func(f *Framework) error {
-		f.configLoaders = append(f.configLoaders, func(app *App) error {
+		f.configLoaders = append(f.configLoaders, configLoader{load: func(app *App) error {
 			if err := app.LoadConfigFromBytes(data); err != nil {
 				return fmt.Errorf("load config from bytes failed: %w", err)
 			}
 			return nil
-		})
+		}})
 		return nil
 	}
 }
@@ -62,12 +95,12 @@ func WithConfigBytes(data []byte) Option {
 // WithConfigMap 从 map 设置配置
 func WithConfigMap(configMap map[string]interface{}) Option {
 	return func(f *Framework) error {
-		f.configLoaders = append(f.configLoaders, func(app *App) error {
+		f.configLoaders = append(f.configLoaders, configLoader{load: func(app *App) error {
 			if err := app.LoadConfigFromMap(configMap); err != nil {
 				return fmt.Errorf("load config from map failed: %w", err)
 			}
 			return nil
-		})
+		}})
 		return nil
 	}
 }
@@ -134,16 +167,62 @@ func NewFramework(options ...Option) (*Framework, error) {
 	return framework, nil
 }
 
+// Initialize 完成日志器、数据库、迁移、HTTP、模块与应用的初始化，重复调用时不会重复执行
+// Run 与 Execute 会按命令自动执行所需的初始化，只在不调用二者而直接使用 GetDB、GetEcho 等时需要手动调用
+func (f *Framework) Initialize() error {
+	return f.initializeTo(stageReady)
+}
+
+// initialize 加载并校验配置，其余初始化延迟到 Initialize、Run 或 Execute
 func (f *Framework) initialize() error {
+	// dotenv 最先加载，使其中的 ORZ_PROFILE、ORZ_CONFIG_KEY 等变量对后续步骤生效
+	if err := f.app.configManager.applyDotEnv(); err != nil {
+		return err
+	}
+	if err := f.parseFlags(); err != nil {
+		return err
+	}
+
 	for _, loader := range f.configLoaders {
-		if err := loader(f.app); err != nil {
+		if err := loader.load(f.app); err != nil {
+			return err
+		}
+	}
+
+	if err := f.app.ValidateConfig(); err != nil {
+		return err
+	}
+
+	// 提前检查模块依赖，缺失或循环依赖时直接返回错误
+	if _, err := sortModules(f.modules); err != nil {
+		return err
+	}
+	f.stage = stageConfig
+	return nil
+}
+
+// initializeTo 执行到 stage 为止尚未执行的初始化阶段
+func (f *Framework) initializeTo(stage initStage) error {
+	if f.stage < stageDatabase && stage >= stageDatabase {
+		if err := f.initializeDatabase(); err != nil {
+			return err
+		}
+		f.stage = stageDatabase
+	}
+	if f.stage < stageReady && stage >= stageReady {
+		if err := f.initializeApplication(); err != nil {
 			return err
 		}
+		f.stage = stageReady
 	}
+	return nil
+}
 
+// initializeDatabase 初始化日志器并连接数据库
+func (f *Framework) initializeDatabase() error {
 	switch f.loggerMode {
 	case loggerModeCustom:
-		f.app.SetLogger(f.customLogger)
+		f.app.SetLogger(f.app.withSecretRedaction(f.customLogger))
 	case loggerModeFromConfig:
 		if err := f.app.EnableLogger(); err != nil {
 			return err
@@ -155,22 +234,40 @@ func (f *Framework) initialize() error {
 			return err
 		}
 	}
+	return nil
+}
+
+// initializeApplication 执行迁移，初始化 HTTP、模块与应用
+func (f *Framework) initializeApplication() error {
+	f.app.watchConfig()
+	f.app.watchLogRotation()
+
+	if err := f.runMigrations(); err != nil {
+		return err
+	}
 
 	if f.enableHTTP {
 		f.app.EnableHTTP()
 	}
 
+	if err := configureModules(f.app, f.modules); err != nil {
+		return err
+	}
+
 	if f.applicationSet && f.application != nil {
 		if err := f.application.Configure(f.app); err != nil {
 			return fmt.Errorf("failed to configure application: %w", err)
 		}
 	}
 
-	return nil
+	return f.prepareModels()
 }
 
 // Run 运行应用
 func (f *Framework) Run() error {
+	if err := f.initializeTo(stageReady); err != nil {
+		return err
+	}
 	return f.app.Run()
 }
 
@@ -261,6 +261,7 @@ func CamelToSnake(s string) string {
 type BaseRepository[T any, ID comparable] struct {
 	tableName string
 	db        *gorm.DB
+	dbName    string
 	getDB     func(ctx context.Context) *gorm.DB
 }
 
@@ -271,6 +272,14 @@ func NewRepository[T any, ID comparable](db *gorm.DB) Repository[T, ID] {
 	}
 }
 
+// NewNamedRepository 创建使用具名数据库连接的仓库实例，只会加入同名连接的事务
+func NewNamedRepository[T any, ID comparable](name string, db *gorm.DB) Repository[T, ID] {
+	return &BaseRepository[T, ID]{
+		db:     db,
+		dbName: name,
+	}
+}
+
 // NewRepositoryWithGetter 创建仓库实例，使用函数获取数据库连接（支持事务）
 func NewRepositoryWithGetter[T any, ID comparable](getDB func(ctx context.Context) *gorm.DB) Repository[T, ID] {
 	return &BaseRepository[T, ID]{
@@ -301,6 +310,19 @@ func NewRepositoryFromApp[T any, ID comparable](app any) (Repository[T, ID], err
 	}
 }
 
+// NewRepositoryFromAppNamed 从应用容器的具名数据库连接创建仓库实例
+func NewRepositoryFromAppNamed[T any, ID comparable](app any, name string) (Repository[T, ID], error) {
+	a, ok := app.(interface{ GetDatabaseNamed(string) *gorm.DB })
+	if !ok {
+		return nil, fmt.Errorf("app does not implement GetDatabaseNamed method")
+	}
+	db := a.GetDatabaseNamed(name)
+	if db == nil {
+		return nil, fmt.Errorf("failed to get database %q from app: database is nil", name)
+	}
+	return NewNamedRepository[T, ID](name, db), nil
+}
+
 // GetDB 获取数据库实例
 func (r *BaseRepository[T, ID]) GetDB(ctx context.Context) *gorm.DB {
 	// 优先从 context 中获取事务连接
@@ -308,13 +330,31 @@ func (r *BaseRepository[T, ID]) GetDB(ctx context.Context) *gorm.DB {
 		return r.getDB(ctx)
 	}
 
-	// 检查 context 中是否有事务（使用统一的上下文键）
-	if tx, ok := ctx.Value(dbContextKey).(*gorm.DB); ok {
+	// 检查 context 中是否有同一连接的事务
+	if tx, ok := ctx.Value(dbContextKeyFor(r.dbName)).(*gorm.DB); ok {
 		return tx
 	}
 	return r.db
 }
 
+// GetReadDB 获取读操作使用的数据库连接
+// 事务中、通过 WithPrimary 强制主库或未配置副本时使用主库，否则按策略选择只读副本
+func (r *BaseRepository[T, ID]) GetReadDB(ctx context.Context) *gorm.DB {
+	if r.getDB != nil {
+		return r.getDB(ctx)
+	}
+
+	if tx, ok := ctx.Value(dbContextKeyFor(r.dbName)).(*gorm.DB); ok {
+		return tx
+	}
+	if !usePrimary(ctx) {
+		if replica := replicaFor(r.db); replica != nil {
+			return replica
+		}
+	}
+	return r.db
+}
+
 // GetTableName 获取表名
 func (r *BaseRepository[T, ID]) GetTableName() string {
 	if r.tableName == "" {
@@ -361,7 +401,7 @@ func (r *BaseRepository[T, ID]) CreateOrUpdate(ctx context.Context, entity *T) e
 // FindById 根据ID查找实体
 func (r *BaseRepository[T, ID]) FindById(ctx context.Context, id ID) (T, error) {
 	var entity T
-	db := r.GetDB(ctx)
+	db := r.GetReadDB(ctx)
 	err := db.Table(r.GetTableName()).Where("id = ?", id).First(&entity).Error
 	return entity, err
 }
@@ -386,7 +426,7 @@ func (r *BaseRepository[T, ID]) FindByIdIn(ctx context.Context, ids []ID) ([]T,
 	if len(ids) == 0 {
 		return entities, nil
 	}
-	db := r.GetDB(ctx)
+	db := r.GetReadDB(ctx)
 	err := db.Table(r.GetTableName()).Where("id in ?", ids).Find(&entities).Error
 	return entities, err
 }
@@ -394,7 +434,7 @@ func (r *BaseRepository[T, ID]) FindByIdIn(ctx context.Context, ids []ID) ([]T,
 // FindAll 查找所有实体
 func (r *BaseRepository[T, ID]) FindAll(ctx context.Context) ([]T, error) {
 	var entities []T
-	db := r.GetDB(ctx)
+	db := r.GetReadDB(ctx)
 	err := db.Table(r.GetTableName()).Find(&entities).Error
 	return entities, err
 }
@@ -402,7 +442,7 @@ func (r *BaseRepository[T, ID]) FindAll(ctx context.Context) ([]T, error) {
 // ExistsById 检查实体是否存在
 func (r *BaseRepository[T, ID]) ExistsById(ctx context.Context, id ID) (bool, error) {
 	var count int64
-	db := r.GetDB(ctx)
+	db := r.GetReadDB(ctx)
 	err := db.Table(r.GetTableName()).Where("id = ?", id).Count(&count).Error
 	return count > 0, err
 }
@@ -443,7 +483,7 @@ func (r *BaseRepository[T, ID]) DeleteByIdIn(ctx context.Context, ids []ID) erro
 
 // Count 统计实体数量
 func (r *BaseRepository[T, ID]) Count(ctx context.Context) (int64, error) {
-	db := r.GetDB(ctx)
+	db := r.GetReadDB(ctx)
 	var total int64
 	err := db.Table(r.GetTableName()).Count(&total).Error
 	return total, err
@@ -452,7 +492,7 @@ func (r *BaseRepository[T, ID]) Count(ctx context.Context) (int64, error) {
 // Find 条件查询
 func (r *BaseRepository[T, ID]) Find(ctx context.Context, matchers []Matcher, sort Sort) ([]T, error) {
 	var items []T
-	db := r.GetDB(ctx)
+	db := r.GetReadDB(ctx)
 
 	// 验证排序字段安全性
 	sortCopy := sort // 创建副本避免修改原始对象
@@ -726,7 +766,7 @@ func entityIDValue(entity any) (any, error) {
 // FindOne 查找单个实体
 func (r *BaseRepository[T, ID]) FindOne(ctx context.Context, matchers []Matcher) (T, error) {
 	var entity T
-	db := r.GetDB(ctx)
+	db := r.GetReadDB(ctx)
 
 	db, err := r.match(db, matchers)
 	if err != nil {
@@ -745,7 +785,7 @@ func (r *BaseRepository[T, ID]) Exists(ctx context.Context, matchers []Matcher)
 
 // CountByMatchers 根据条件统计数量
 func (r *BaseRepository[T, ID]) CountByMatchers(ctx context.Context, matchers []Matcher) (int64, error) {
-	db := r.GetDB(ctx)
+	db := r.GetReadDB(ctx)
 
 	db, err := r.match(db, matchers)
 	if err != nil {
@@ -243,7 +243,7 @@ func ExecuteAsTyped[R any, T any, ID comparable](ctx context.Context, builder *P
 }
 
 func (b *PageBuilder[T, ID]) buildBaseQuery(ctx context.Context) (*gorm.DB, error) {
-	db := b.repo.GetDB(ctx).Table(b.repo.GetTableName())
+	db := b.readDB(ctx).Table(b.repo.GetTableName())
 
 	for _, join := range b.joins {
 		db = db.Joins(join)
@@ -258,6 +258,16 @@ func (b *PageBuilder[T, ID]) buildBaseQuery(ctx context.Context) (*gorm.DB, erro
 	return db, nil
 }
 
+// readDB 分页查询属于读操作，仓库支持读写分离时使用只读连接
+func (b *PageBuilder[T, ID]) readDB(ctx context.Context) *gorm.DB {
+	if repo, ok := b.repo.(interface {
+		GetReadDB(ctx context.Context) *gorm.DB
+	}); ok {
+		return repo.GetReadDB(ctx)
+	}
+	return b.repo.GetDB(ctx)
+}
+
 func (b *PageBuilder[T, ID]) buildCountQuery(ctx context.Context) (*gorm.DB, error) {
 	db, err := b.buildBaseQuery(ctx)
 	if err != nil {
@@ -0,0 +1,97 @@
+package orz
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/labstack/echo/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	loggerContextKey    contextKey = "logger"
+	requestIDContextKey contextKey = "request_id"
+	principalContextKey contextKey = "principal"
+	appContextKey       contextKey = "app"
+)
+
+// ContextWithLogger 将日志器放入上下文，之后通过 LoggerFrom 获取
+func ContextWithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey, logger)
+}
+
+// LoggerFrom 获取上下文中的日志器
+// HTTP 请求中返回带请求字段的日志器，Service.Transaction 回调与仓库方法中同样可用；
+// 上下文由 App.Context 派生时（后台任务、生命周期钩子、命令）返回应用日志器，否则返回 zap.L()
+func LoggerFrom(ctx context.Context) *zap.Logger {
+	if ctx == nil {
+		return zap.L()
+	}
+	if logger, ok := ctx.Value(loggerContextKey).(*zap.Logger); ok {
+		return logger
+	}
+	if app, ok := ctx.Value(appContextKey).(*App); ok {
+		return app.Logger()
+	}
+	return zap.L()
+}
+
+// RequestIDFrom 获取上下文中的请求 ID，不在请求中时返回空字符串
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
+
+// RequestLogger 返回为每个请求创建子日志器的中间件，EnableHTTP 会自动注册
+// 日志器名称为 http，带有 request_id、method、route 与 ip 字段，请求 ID 取自 X-Request-Id 请求头，没有时生成并写入响应头
+func (a *App) RequestLogger() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			req := c.Request()
+			id := req.Header.Get(echo.HeaderXRequestID)
+			if id == "" {
+				id = c.Response().Header().Get(echo.HeaderXRequestID)
+			}
+			if id == "" {
+				id = newRequestID()
+			}
+			c.Response().Header().Set(echo.HeaderXRequestID, id)
+
+			route := c.Path()
+			if route == "" {
+				route = req.URL.Path
+			}
+			logger := a.Logger().Named("http").With(
+				zap.String("request_id", id),
+				zap.String("method", req.Method),
+				zap.String("route", route),
+				zap.String("ip", c.RealIP()),
+			)
+
+			ctx := context.WithValue(req.Context(), requestIDContextKey, id)
+			c.SetRequest(req.WithContext(ContextWithLogger(ctx, logger)))
+			return next(c)
+		}
+	}
+}
+
+// SetPrincipal 在认证通过后记录请求的身份，之后 LoggerFrom 返回的日志器与访问日志带有 principal 字段
+func SetPrincipal(c *echo.Context, principal string) {
+	req := c.Request()
+	logger := LoggerFrom(req.Context()).With(zap.String("principal", principal))
+	ctx := context.WithValue(req.Context(), principalContextKey, principal)
+	c.SetRequest(req.WithContext(ContextWithLogger(ctx, logger)))
+}
+
+// PrincipalFrom 获取通过 SetPrincipal 记录的身份
+func PrincipalFrom(ctx context.Context) string {
+	principal, _ := ctx.Value(principalContextKey).(string)
+	return principal
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
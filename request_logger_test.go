@@ -0,0 +1,108 @@
+package orz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRequestLoggerAddsRequestFields(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	app := NewApp()
+	if err := app.LoadConfigFromMap(map[string]interface{}{
+		"server": map[string]interface{}{"ip_extractor": "x-forwarded-for", "ip_trust_list": []string{"10.0.0.0/8"}},
+	}); err != nil {
+		t.Fatalf("LoadConfigFromMap returned error: %v", err)
+	}
+	app.SetLogger(zap.New(core))
+	app.EnableHTTP()
+
+	var requestID string
+	e := app.GetEcho()
+	e.GET("/users/:id", func(c *echo.Context) error {
+		SetPrincipal(c, "user-42")
+		// 模拟仓库或事务回调拿到派生的上下文
+		ctx, cancel := context.WithCancel(c.Request().Context())
+		defer cancel()
+		LoggerFrom(ctx).Info("loading user")
+		requestID = RequestIDFrom(ctx)
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	req.RemoteAddr = "10.0.0.2:1234"
+	req.Header.Set(echo.HeaderXForwardedFor, "203.0.113.9, 10.0.0.2")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status %d", rec.Code)
+	}
+	if requestID == "" || rec.Header().Get(echo.HeaderXRequestID) != requestID {
+		t.Fatalf("expected generated request ID %q in response header, got %q", requestID, rec.Header().Get(echo.HeaderXRequestID))
+	}
+
+	entries := logs.FilterMessage("loading user").All()
+	if len(entries) != 1 {
+		t.Fatalf("expected one log entry, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	expected := map[string]string{
+		"request_id": requestID,
+		"method":     http.MethodGet,
+		"route":      "/users/:id",
+		"ip":         "203.0.113.9",
+		"principal":  "user-42",
+	}
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Fatalf("expected field %s=%q, got %v", key, value, fields)
+		}
+	}
+}
+
+func TestRequestLoggerKeepsIncomingRequestID(t *testing.T) {
+	app := NewApp()
+	app.SetLogger(zap.NewNop())
+	app.EnableHTTP()
+
+	var requestID string
+	e := app.GetEcho()
+	e.GET("/ping", func(c *echo.Context) error {
+		requestID = RequestIDFrom(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(echo.HeaderXRequestID, "abc-123")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if requestID != "abc-123" || rec.Header().Get(echo.HeaderXRequestID) != "abc-123" {
+		t.Fatalf("expected incoming request ID to be kept, got %q / %q", requestID, rec.Header().Get(echo.HeaderXRequestID))
+	}
+}
+
+func TestLoggerFromFallsBackToAppLogger(t *testing.T) {
+	app := NewApp()
+	logger := zap.NewNop()
+	app.SetLogger(logger)
+
+	ctx, cancel := context.WithCancel(app.Context())
+	defer cancel()
+	if LoggerFrom(ctx) != logger {
+		t.Fatal("expected contexts derived from the app context to use the app logger")
+	}
+	if LoggerFrom(context.Background()) != zap.L() {
+		t.Fatal("expected unrelated contexts to use the global logger")
+	}
+	if RequestIDFrom(ctx) != "" {
+		t.Fatal("expected no request ID outside requests")
+	}
+}
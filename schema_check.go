@@ -0,0 +1,132 @@
+package orz
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// SchemaIssue 模型与数据库实际结构之间的差异
+type SchemaIssue struct {
+	Table   string
+	Column  string
+	Message string
+}
+
+func (i SchemaIssue) String() string {
+	if i.Column == "" {
+		return fmt.Sprintf("%s: %s", i.Table, i.Message)
+	}
+	return fmt.Sprintf("%s.%s: %s", i.Table, i.Column, i.Message)
+}
+
+// columnTypeFamilies 可以互相兼容的列类型分组
+// 不同数据库对同一 Go 类型的列类型命名不同，只在两个类型都能识别且不属于同一分组时才报告差异
+var columnTypeFamilies = [][]string{
+	{"int", "integer", "tinyint", "smallint", "mediumint", "bigint", "int2", "int4", "int8", "serial", "bigserial", "smallserial"},
+	{"bool", "boolean", "tinyint", "bit", "numeric"},
+	{"varchar", "char", "character", "character varying", "bpchar", "nvarchar", "nchar", "text", "tinytext", "mediumtext", "longtext", "string", "uuid"},
+	{"float", "double", "double precision", "real", "decimal", "numeric", "float4", "float8"},
+	{"datetime", "datetime2", "timestamp", "timestamptz", "timestamp with time zone", "timestamp without time zone", "date", "time", "timetz"},
+	{"blob", "tinyblob", "mediumblob", "longblob", "bytea", "binary", "varbinary"},
+	{"json", "jsonb"},
+}
+
+// columnTypeStopWords 列定义中类型名之后的修饰关键字
+var columnTypeStopWords = map[string]bool{
+	"not": true, "null": true, "default": true, "auto_increment": true, "autoincrement": true,
+	"primary": true, "unique": true, "unsigned": true, "check": true, "generated": true,
+	"collate": true, "comment": true, "references": true,
+}
+
+// CheckSchema 对比模型与数据库实际结构，报告缺失的表、缺失的列和不兼容的列类型
+func CheckSchema(db *gorm.DB, models ...any) ([]SchemaIssue, error) {
+	var issues []SchemaIssue
+	migrator := db.Migrator()
+
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+		table := stmt.Schema.Table
+
+		if !migrator.HasTable(model) {
+			issues = append(issues, SchemaIssue{Table: table, Message: "table does not exist"})
+			continue
+		}
+
+		columnTypes, err := migrator.ColumnTypes(model)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+		}
+		actual := make(map[string]string, len(columnTypes))
+		for _, columnType := range columnTypes {
+			actual[strings.ToLower(columnType.Name())] = columnType.DatabaseTypeName()
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+
+			actualType, ok := actual[strings.ToLower(field.DBName)]
+			if !ok {
+				issues = append(issues, SchemaIssue{Table: table, Column: field.DBName, Message: "column does not exist"})
+				continue
+			}
+
+			expectedType := baseColumnType(migrator.FullDataTypeOf(field).SQL)
+			if !compatibleColumnTypes(expectedType, baseColumnType(actualType)) {
+				issues = append(issues, SchemaIssue{
+					Table:   table,
+					Column:  field.DBName,
+					Message: fmt.Sprintf("type mismatch: model expects %s, database has %s", expectedType, strings.ToLower(actualType)),
+				})
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+// baseColumnType 从列定义中提取类型名，例如 "varchar(191) NOT NULL" -> "varchar"
+func baseColumnType(definition string) string {
+	definition = strings.ToLower(definition)
+	if i := strings.IndexByte(definition, '('); i >= 0 {
+		definition = definition[:i]
+	}
+
+	var words []string
+	for _, word := range strings.Fields(definition) {
+		if columnTypeStopWords[word] {
+			break
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " ")
+}
+
+func compatibleColumnTypes(expected, actual string) bool {
+	if expected == "" || actual == "" || expected == actual {
+		return true
+	}
+
+	expectedKnown, actualKnown := false, false
+	for _, family := range columnTypeFamilies {
+		hasExpected, hasActual := false, false
+		for _, name := range family {
+			hasExpected = hasExpected || name == expected
+			hasActual = hasActual || name == actual
+		}
+		if hasExpected && hasActual {
+			return true
+		}
+		expectedKnown = expectedKnown || hasExpected
+		actualKnown = actualKnown || hasActual
+	}
+
+	// 无法识别的类型不做判断，避免误报
+	return !expectedKnown || !actualKnown
+}
@@ -14,10 +14,19 @@ const (
 	dbContextKey contextKey = "db"
 )
 
+// dbContextKeyFor 返回具名数据库连接在上下文中的键，默认数据库沿用 dbContextKey
+func dbContextKeyFor(name string) contextKey {
+	if name == "" || name == DefaultDatabaseName {
+		return dbContextKey
+	}
+	return contextKey("db:" + name)
+}
+
 // Service 基础服务类，提供事务管理和数据库访问能力
 // 用户自定义的service可以继承它，专注于业务逻辑
 type Service struct {
-	db *gorm.DB // 持有数据库连接
+	db     *gorm.DB // 持有数据库连接
+	dbName string   // 数据库连接名称，决定事务在上下文中的作用范围
 }
 
 // NewService 创建服务实例
@@ -25,9 +34,15 @@ func NewService(db *gorm.DB) *Service {
 	return &Service{db: db}
 }
 
+// NewNamedService 创建使用具名数据库连接的服务实例
+// 事务只对同名连接创建的仓库可见
+func NewNamedService(name string, db *gorm.DB) *Service {
+	return &Service{db: db, dbName: name}
+}
+
 // InTransaction 检查是否在事务中
 func (s *Service) InTransaction(ctx context.Context) bool {
-	_, ok := ctx.Value(dbContextKey).(*gorm.DB)
+	_, ok := ctx.Value(dbContextKeyFor(s.dbName)).(*gorm.DB)
 	return ok
 }
 
@@ -36,7 +51,7 @@ func (s *Service) Transaction(ctx context.Context, f func(ctx context.Context) e
 	if !s.InTransaction(ctx) {
 		// 使用 Service 持有的数据库连接
 		return s.db.Transaction(func(tx *gorm.DB) error {
-			c := context.WithValue(ctx, dbContextKey, tx)
+			c := context.WithValue(ctx, dbContextKeyFor(s.dbName), tx)
 			return f(c)
 		})
 	}
@@ -48,6 +63,11 @@ func WithTx(ctx context.Context, tx *gorm.DB) context.Context {
 	return context.WithValue(ctx, dbContextKey, tx)
 }
 
+// WithNamedTx 将具名数据库连接的事务放入上下文
+func WithNamedTx(ctx context.Context, name string, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, dbContextKeyFor(name), tx)
+}
+
 // WithDB 将数据库连接放入上下文（用于非事务场景）
 func WithDB(ctx context.Context, db *gorm.DB) context.Context {
 	return context.WithValue(ctx, dbContextKey, db)
@@ -0,0 +1,57 @@
+package orz
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type serviceTestEntity struct {
+	ID uint `gorm:"primaryKey"`
+}
+
+func TestNamedTransactionsOnlyApplyToMatchingRepositories(t *testing.T) {
+	primary := &gorm.DB{}
+	analytics := &gorm.DB{}
+	primaryTx := &gorm.DB{}
+	analyticsTx := &gorm.DB{}
+
+	primaryRepo := NewRepository[serviceTestEntity, uint](primary)
+	analyticsRepo := NewNamedRepository[serviceTestEntity, uint]("analytics", analytics)
+
+	ctx := WithTx(context.Background(), primaryTx)
+	if primaryRepo.GetDB(ctx) != primaryTx {
+		t.Fatal("expected default repository to join the default transaction")
+	}
+	if analyticsRepo.GetDB(ctx) != analytics {
+		t.Fatal("expected analytics repository to ignore the default transaction")
+	}
+
+	ctx = WithNamedTx(ctx, "analytics", analyticsTx)
+	if analyticsRepo.GetDB(ctx) != analyticsTx {
+		t.Fatal("expected analytics repository to join the analytics transaction")
+	}
+	if primaryRepo.GetDB(ctx) != primaryTx {
+		t.Fatal("expected default repository to keep the default transaction")
+	}
+
+	if !NewNamedService("analytics", analytics).InTransaction(ctx) {
+		t.Fatal("expected analytics service to detect its transaction")
+	}
+	if NewNamedService("analytics", analytics).InTransaction(WithTx(context.Background(), primaryTx)) {
+		t.Fatal("expected analytics service to ignore the default transaction")
+	}
+}
+
+func TestNewRepositoryFromAppNamedRequiresConnection(t *testing.T) {
+	app := NewApp()
+	app.SetDatabaseNamed("analytics", &gorm.DB{})
+
+	if _, err := NewRepositoryFromAppNamed[serviceTestEntity, uint](app, "analytics"); err != nil {
+		t.Fatalf("NewRepositoryFromAppNamed returned error: %v", err)
+	}
+	if _, err := NewRepositoryFromAppNamed[serviceTestEntity, uint](app, "missing"); err == nil {
+		t.Fatal("expected error for unknown database name")
+	}
+}
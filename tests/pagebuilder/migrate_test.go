@@ -0,0 +1,228 @@
+package pagebuilderintegration
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-orz/orz"
+	"github.com/go-orz/orz/migrate"
+	"gorm.io/gorm"
+)
+
+var migrationFS = fstest.MapFS{
+	"migrations/0001_create_accounts.up.sql":   {Data: []byte("CREATE TABLE accounts (id INTEGER PRIMARY KEY, name TEXT NOT NULL);")},
+	"migrations/0001_create_accounts.down.sql": {Data: []byte("DROP TABLE accounts;")},
+	"migrations/0002_add_email.up.sql":         {Data: []byte("ALTER TABLE accounts ADD COLUMN email TEXT;\nCREATE INDEX idx_accounts_email ON accounts (email);")},
+	"migrations/0002_add_email.down.sql":       {Data: []byte("DROP INDEX idx_accounts_email;\nALTER TABLE accounts DROP COLUMN email;")},
+}
+
+var seedMigration = migrate.Migration{
+	Version: 3,
+	Name:    "seed_admin",
+	Up: func(ctx context.Context, tx *gorm.DB) error {
+		return tx.Exec("INSERT INTO accounts (name, email) VALUES (?, ?)", "admin", "admin@example.com").Error
+	},
+	Down: func(ctx context.Context, tx *gorm.DB) error {
+		return tx.Exec("DELETE FROM accounts WHERE name = ?", "admin").Error
+	},
+}
+
+func TestMigratorUpDownStatus(t *testing.T) {
+	db := newFileSQLiteDB(t)
+	m := migrate.New(db)
+	if err := m.AddFS(migrationFS); err != nil {
+		t.Fatalf("AddFS returned error: %v", err)
+	}
+	if err := m.Add(seedMigration); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up returned error: %v", err)
+	}
+	if len(applied) != 3 {
+		t.Fatalf("expected 3 applied migrations, got %d", len(applied))
+	}
+
+	applied, err = m.Up(ctx)
+	if err != nil || len(applied) != 0 {
+		t.Fatalf("expected second Up to be a no-op, got %d, %v", len(applied), err)
+	}
+
+	var count int64
+	if err := db.Table("accounts").Where("email = ?", "admin@example.com").Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("expected seeded account, got %d, %v", count, err)
+	}
+
+	reverted, err := m.Down(ctx, 2)
+	if err != nil {
+		t.Fatalf("Down returned error: %v", err)
+	}
+	if len(reverted) != 2 || reverted[0].Version != 3 || reverted[1].Version != 2 {
+		t.Fatalf("unexpected reverted migrations: %+v", reverted)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+	if len(statuses) != 3 || !statuses[0].Applied || statuses[1].Applied || statuses[2].Applied {
+		t.Fatalf("unexpected migration status: %+v", statuses)
+	}
+	if db.Migrator().HasColumn("accounts", "email") {
+		t.Fatal("expected email column to be dropped")
+	}
+}
+
+func TestWithMigrationsRunsBeforeApplication(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.db")
+	hasTable := false
+
+	framework, err := orz.NewFramework(
+		orz.WithConfigMap(map[string]interface{}{
+			"database": map[string]interface{}{
+				"type":   "sqlite",
+				"sqlite": map[string]interface{}{"path": path},
+			},
+		}),
+		orz.WithDatabase(),
+		orz.WithMigrations(migrationFS, seedMigration),
+		orz.WithApplication(orz.NewSimpleApp(func(app *orz.App) error {
+			hasTable = app.GetDatabase().Migrator().HasTable("accounts")
+			return nil
+		})),
+	)
+	if err != nil {
+		t.Fatalf("NewFramework returned error: %v", err)
+	}
+	if err := framework.Initialize(); err != nil {
+		t.Fatalf("Initialize returned error: %v", err)
+	}
+	if !hasTable {
+		t.Fatal("expected migrations to run before the application is configured")
+	}
+}
+
+func newFileSQLiteDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := orz.ConnectDatabase(orz.DatabaseConfig{
+		Type: orz.DatabaseSqlite,
+		Sqlite: orz.SqliteConfig{
+			Path: filepath.Join(t.TempDir(), "test.db"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("ConnectDatabase returned error: %v", err)
+	}
+
+	return db
+}
+
+func TestExecuteMigrateCommands(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.db")
+
+	framework, err := orz.NewFramework(
+		orz.WithConfigMap(map[string]interface{}{
+			"database": map[string]interface{}{
+				"type":   "sqlite",
+				"sqlite": map[string]interface{}{"path": path},
+			},
+		}),
+		orz.WithDatabase(),
+		orz.WithMigrations(migrationFS, seedMigration),
+	)
+	if err != nil {
+		t.Fatalf("NewFramework returned error: %v", err)
+	}
+
+	if err := framework.Execute([]string{"svc", "migrate", "up"}); err != nil {
+		t.Fatalf("migrate up returned error: %v", err)
+	}
+	if !framework.GetDB().Migrator().HasColumn("accounts", "email") {
+		t.Fatal("expected migrate up to apply all migrations")
+	}
+
+	if err := framework.Execute([]string{"svc", "migrate", "down", "2"}); err != nil {
+		t.Fatalf("migrate down returned error: %v", err)
+	}
+	if framework.GetDB().Migrator().HasColumn("accounts", "email") {
+		t.Fatal("expected migrate down to drop the email column")
+	}
+
+	if err := framework.Execute([]string{"svc", "migrate", "up"}); err != nil {
+		t.Fatalf("migrate up returned error: %v", err)
+	}
+	if !framework.GetDB().Migrator().HasColumn("accounts", "email") {
+		t.Fatal("expected migrate up to re-apply the email column")
+	}
+
+	if err := framework.Execute([]string{"svc", "migrate", "down", "zero"}); err == nil {
+		t.Fatal("expected invalid step count to be rejected")
+	}
+}
+
+func TestExecuteMigrateStatusOnFreshDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.db")
+	configured := false
+
+	framework, err := orz.NewFramework(
+		orz.WithConfigMap(map[string]interface{}{
+			"database": map[string]interface{}{
+				"type":   "sqlite",
+				"sqlite": map[string]interface{}{"path": path},
+			},
+		}),
+		orz.WithDatabase(),
+		orz.WithMigrations(migrationFS, seedMigration),
+		orz.WithApplication(orz.NewSimpleApp(func(app *orz.App) error {
+			configured = true
+			return nil
+		})),
+	)
+	if err != nil {
+		t.Fatalf("NewFramework returned error: %v", err)
+	}
+
+	output := captureStdout(t, func() {
+		if err := framework.Execute([]string{"svc", "migrate", "status"}); err != nil {
+			t.Fatalf("migrate status returned error: %v", err)
+		}
+	})
+	if configured {
+		t.Fatal("expected migrate to skip application configuration")
+	}
+	if strings.Count(output, "pending") != 3 || strings.Contains(output, "applied") {
+		t.Fatalf("expected all migrations to be pending on a fresh database, got:\n%s", output)
+	}
+	if framework.GetDB().Migrator().HasTable("accounts") {
+		t.Fatal("expected migrations not to run before migrate up")
+	}
+}
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Pipe returned error: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	w.Close()
+	output, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll returned error: %v", err)
+	}
+	return string(output)
+}
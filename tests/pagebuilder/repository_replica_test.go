@@ -0,0 +1,195 @@
+package pagebuilderintegration
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type replicaUser struct {
+	ID   uint `gorm:"primaryKey"`
+	Name string
+}
+
+func (replicaUser) TableName() string {
+	return "replica_users"
+}
+
+func TestRepositoryRoutesReadsToReplica(t *testing.T) {
+	dir := t.TempDir()
+	db, err := orz.ConnectDatabase(orz.DatabaseConfig{
+		Type:   orz.DatabaseSqlite,
+		Sqlite: orz.SqliteConfig{Path: filepath.Join(dir, "primary.db")},
+		Replicas: []orz.DatabaseConfig{
+			{Sqlite: orz.SqliteConfig{Path: filepath.Join(dir, "replica.db")}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ConnectDatabase returned error: %v", err)
+	}
+
+	replica, err := orz.ConnectDatabase(orz.DatabaseConfig{
+		Type:   orz.DatabaseSqlite,
+		Sqlite: orz.SqliteConfig{Path: filepath.Join(dir, "replica.db")},
+	})
+	if err != nil {
+		t.Fatalf("ConnectDatabase returned error: %v", err)
+	}
+	for _, conn := range []*gorm.DB{db, replica} {
+		if err := conn.AutoMigrate(&replicaUser{}); err != nil {
+			t.Fatalf("AutoMigrate returned error: %v", err)
+		}
+	}
+
+	repo := orz.NewRepository[replicaUser, uint](db)
+	ctx := context.Background()
+	if err := repo.Create(ctx, &replicaUser{ID: 1, Name: "primary"}); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if err := replica.Create(&replicaUser{ID: 1, Name: "replica"}).Error; err != nil {
+		t.Fatalf("create replica row returned error: %v", err)
+	}
+
+	user, err := repo.FindById(ctx, 1)
+	if err != nil {
+		t.Fatalf("FindById returned error: %v", err)
+	}
+	if user.Name != "replica" {
+		t.Fatalf("expected read from replica, got %q", user.Name)
+	}
+
+	page, err := orz.NewPageBuilder(repo).Execute(ctx)
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].Name != "replica" {
+		t.Fatalf("expected paged read from replica, got %+v", page.Items)
+	}
+
+	user, err = repo.FindById(orz.WithPrimary(ctx), 1)
+	if err != nil {
+		t.Fatalf("FindById returned error: %v", err)
+	}
+	if user.Name != "primary" {
+		t.Fatalf("expected WithPrimary to read from primary, got %q", user.Name)
+	}
+
+	service := orz.NewService(db)
+	err = service.Transaction(ctx, func(txCtx context.Context) error {
+		user, err := repo.FindById(txCtx, 1)
+		if err != nil {
+			return err
+		}
+		if user.Name != "primary" {
+			t.Fatalf("expected transaction to read from primary, got %q", user.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction returned error: %v", err)
+	}
+}
+
+func TestRepositoryOnTransactionReadsOwnWrites(t *testing.T) {
+	dir := t.TempDir()
+	db, err := orz.ConnectDatabase(orz.DatabaseConfig{
+		Type:   orz.DatabaseSqlite,
+		Sqlite: orz.SqliteConfig{Path: filepath.Join(dir, "primary.db")},
+		Replicas: []orz.DatabaseConfig{
+			{Sqlite: orz.SqliteConfig{Path: filepath.Join(dir, "replica.db")}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ConnectDatabase returned error: %v", err)
+	}
+	if err := db.AutoMigrate(&replicaUser{}); err != nil {
+		t.Fatalf("AutoMigrate returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		repo := orz.NewRepository[replicaUser, uint](tx)
+		if err := repo.Create(ctx, &replicaUser{ID: 1, Name: "primary"}); err != nil {
+			return err
+		}
+		user, err := repo.FindById(ctx, 1)
+		if err != nil {
+			return err
+		}
+		if user.Name != "primary" {
+			t.Fatalf("expected transaction to read its own write, got %q", user.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction returned error: %v", err)
+	}
+}
+
+const trackedSqlite orz.DatabaseType = "tracked_sqlite"
+
+var (
+	registerTrackedSqlite sync.Once
+	trackedMu             sync.Mutex
+	trackedDatabases      []*gorm.DB
+)
+
+// useTrackedSqlite 注册记录所有已打开连接的 sqlite 驱动
+func useTrackedSqlite(t *testing.T) {
+	t.Helper()
+
+	registerTrackedSqlite.Do(func() {
+		orz.RegisterDatabaseDriver(func(cfg orz.DatabaseConfig, logger gormlogger.Interface) (*gorm.DB, error) {
+			cfg.Type, cfg.Replicas = orz.DatabaseSqlite, nil
+			db, err := orz.ConnectDatabase(cfg)
+			if err != nil {
+				return nil, err
+			}
+			trackedMu.Lock()
+			defer trackedMu.Unlock()
+			trackedDatabases = append(trackedDatabases, db)
+			return db, nil
+		}, trackedSqlite)
+	})
+
+	trackedMu.Lock()
+	defer trackedMu.Unlock()
+	trackedDatabases = nil
+}
+
+func TestConnectDatabaseClosesConnectionsWhenReplicaFails(t *testing.T) {
+	useTrackedSqlite(t)
+
+	dir := t.TempDir()
+	_, err := orz.ConnectDatabase(orz.DatabaseConfig{
+		Type:   trackedSqlite,
+		Sqlite: orz.SqliteConfig{Path: filepath.Join(dir, "primary.db")},
+		Replicas: []orz.DatabaseConfig{
+			{Sqlite: orz.SqliteConfig{Path: filepath.Join(dir, "replica.db")}},
+			{Type: "unregistered"},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected replica connection to fail")
+	}
+
+	trackedMu.Lock()
+	defer trackedMu.Unlock()
+	if len(trackedDatabases) != 2 {
+		t.Fatalf("expected primary and first replica to be opened, got %d", len(trackedDatabases))
+	}
+	for i, db := range trackedDatabases {
+		sqlDB, err := db.DB()
+		if err != nil {
+			t.Fatalf("DB returned error: %v", err)
+		}
+		if err := sqlDB.Ping(); err == nil {
+			t.Fatalf("expected connection %d to be closed", i)
+		}
+	}
+}
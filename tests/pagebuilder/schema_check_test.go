@@ -0,0 +1,121 @@
+package pagebuilderintegration
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-orz/orz"
+)
+
+type schemaAccount struct {
+	ID        uint `gorm:"primaryKey"`
+	Name      string
+	Email     string
+	Balance   int64
+	CreatedAt time.Time
+}
+
+func (schemaAccount) TableName() string {
+	return "accounts"
+}
+
+type schemaAudit struct {
+	ID     uint `gorm:"primaryKey"`
+	Action string
+}
+
+func TestCheckSchemaReportsDrift(t *testing.T) {
+	db := newFileSQLiteDB(t)
+	if err := db.Exec("CREATE TABLE accounts (id INTEGER PRIMARY KEY, name TEXT NOT NULL, balance TEXT, created_at DATETIME)").Error; err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	issues, err := orz.CheckSchema(db, &schemaAccount{}, &schemaAudit{})
+	if err != nil {
+		t.Fatalf("CheckSchema returned error: %v", err)
+	}
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	expected := []string{
+		"accounts.email: column does not exist",
+		"accounts.balance: type mismatch: model expects integer, database has text",
+		"schema_audits: table does not exist",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected issues:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestCheckSchemaAcceptsAutoMigratedTables(t *testing.T) {
+	db := newFileSQLiteDB(t)
+	if err := db.AutoMigrate(&schemaAccount{}, &schemaAudit{}); err != nil {
+		t.Fatalf("AutoMigrate returned error: %v", err)
+	}
+
+	issues, err := orz.CheckSchema(db, &schemaAccount{}, &schemaAudit{})
+	if err != nil {
+		t.Fatalf("CheckSchema returned error: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("expected no issues, got %v", issues)
+	}
+}
+
+func TestRegisteredModelsAutoMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.db")
+
+	framework, err := orz.NewFramework(
+		orz.WithConfigMap(map[string]interface{}{
+			"database": map[string]interface{}{
+				"type":         "sqlite",
+				"sqlite":       map[string]interface{}{"path": path},
+				"auto_migrate": true,
+			},
+		}),
+		orz.WithDatabase(),
+		orz.WithApplication(orz.NewSimpleApp(func(app *orz.App) error {
+			app.RegisterModels(&schemaAccount{}, &schemaAudit{})
+			return nil
+		})),
+	)
+	if err != nil {
+		t.Fatalf("NewFramework returned error: %v", err)
+	}
+	if err := framework.Initialize(); err != nil {
+		t.Fatalf("Initialize returned error: %v", err)
+	}
+	if !framework.GetDB().Migrator().HasTable(&schemaAudit{}) {
+		t.Fatal("expected registered models to be auto migrated")
+	}
+}
+
+func TestRegisteredModelsSchemaCheckFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.db")
+
+	framework, err := orz.NewFramework(
+		orz.WithConfigMap(map[string]interface{}{
+			"database": map[string]interface{}{
+				"type":         "sqlite",
+				"sqlite":       map[string]interface{}{"path": path},
+				"schema_check": "fail",
+			},
+		}),
+		orz.WithDatabase(),
+		orz.WithApplication(orz.NewSimpleApp(func(app *orz.App) error {
+			app.RegisterModels(&schemaAudit{})
+			return nil
+		})),
+	)
+	if err != nil {
+		t.Fatalf("NewFramework returned error: %v", err)
+	}
+	err = framework.Initialize()
+	if err == nil || !strings.Contains(err.Error(), "schema_audits: table does not exist") {
+		t.Fatalf("expected schema check failure, got %v", err)
+	}
+}
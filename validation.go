@@ -0,0 +1,376 @@
+package orz
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FieldError 单个配置项的校验错误
+type FieldError struct {
+	Path    string // 配置路径，例如 database.mysql.port
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ValidationErrors 汇总的配置校验错误
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldError := range e {
+		messages[i] = fieldError.Error()
+	}
+	return "invalid config: " + strings.Join(messages, "; ")
+}
+
+// ConfigValidator 可参与配置校验的配置结构
+type ConfigValidator interface {
+	ValidateConfig(v *Validation)
+}
+
+// Validation 收集配置校验错误，错误路径自动带上当前前缀
+type Validation struct {
+	prefix string
+	errors *ValidationErrors
+}
+
+// NewValidation 创建配置校验器
+func NewValidation() *Validation {
+	return &Validation{errors: &ValidationErrors{}}
+}
+
+// At 返回以 path 为前缀的子校验器，错误汇总到同一份报告
+func (v *Validation) At(path string) *Validation {
+	return &Validation{prefix: v.path(path), errors: v.errors}
+}
+
+func (v *Validation) path(field string) string {
+	switch {
+	case v.prefix == "":
+		return field
+	case field == "":
+		return v.prefix
+	case strings.HasPrefix(field, "["):
+		return v.prefix + field
+	default:
+		return v.prefix + "." + field
+	}
+}
+
+// Errorf 记录一条校验错误
+func (v *Validation) Errorf(field, format string, args ...any) {
+	*v.errors = append(*v.errors, FieldError{Path: v.path(field), Message: fmt.Sprintf(format, args...)})
+}
+
+// Required 值为空时记录错误
+func (v *Validation) Required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.Errorf(field, "is required")
+	}
+}
+
+// Range 值不在 [min, max] 范围内时记录错误
+func (v *Validation) Range(field string, value, min, max int) {
+	if value < min || value > max {
+		v.Errorf(field, "must be %d-%d", min, max)
+	}
+}
+
+// OneOf 值不在允许列表中时记录错误，比较时忽略大小写，空值视为未设置
+func (v *Validation) OneOf(field, value string, allowed ...string) {
+	if value == "" {
+		return
+	}
+	for _, candidate := range allowed {
+		if strings.EqualFold(value, candidate) {
+			return
+		}
+	}
+	v.Errorf(field, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+}
+
+// Validate 在 field 路径下校验嵌套配置
+func (v *Validation) Validate(field string, target ConfigValidator) {
+	target.ValidateConfig(v.At(field))
+}
+
+// Err 没有错误时返回 nil，否则返回 ValidationErrors
+func (v *Validation) Err() error {
+	if len(*v.errors) == 0 {
+		return nil
+	}
+	return *v.errors
+}
+
+// Validate 校验框架配置
+func (c *Config) Validate() error {
+	v := NewValidation()
+	c.ValidateConfig(v)
+	return v.Err()
+}
+
+func (c *Config) ValidateConfig(v *Validation) {
+	v.Validate("log", c.Log)
+	v.Validate("server", c.Server)
+	if c.Database.Enabled {
+		v.Validate("database", c.Database)
+	}
+	for _, name := range sortedKeys(c.Databases) {
+		if name == DefaultDatabaseName {
+			v.Errorf("databases."+name, "name %q is reserved for the database block", name)
+			continue
+		}
+		v.Validate("databases."+name, c.Databases[name])
+	}
+}
+
+func (c LogConfig) ValidateConfig(v *Validation) {
+	v.OneOf("level", c.Level, logLevelNames...)
+	for _, name := range sortedKeys(c.Levels) {
+		if _, err := parseLogLevelName(c.Levels[name]); err != nil {
+			v.Errorf("levels."+name, "must be one of %s, got %q", strings.Join(logLevelNames, ", "), c.Levels[name])
+		}
+	}
+	v.OneOf("encode", c.Encode, "console", "json")
+	if c.MaxSize < 0 {
+		v.Errorf("max_size", "must not be negative")
+	}
+	if c.MaxAge < 0 {
+		v.Errorf("max_age", "must not be negative")
+	}
+	v.OneOf("rotate", c.Rotate, logRotateModes...)
+	if c.MaxBackups < 0 {
+		v.Errorf("max_backups", "must not be negative")
+	}
+	if c.FilenamePattern != "" {
+		switch {
+		case !timeRotated(c.Rotate):
+			v.Errorf("filename_pattern", "requires rotate to be daily or hourly")
+		case !strings.Contains(c.FilenamePattern, "%d"):
+			v.Errorf("filename_pattern", "must contain %%d so that files change every day")
+		case strings.EqualFold(c.Rotate, "hourly") && !strings.Contains(c.FilenamePattern, "%H"):
+			v.Errorf("filename_pattern", "must contain %%H for hourly rotation")
+		}
+	}
+	if c.Sampling.Initial < 0 {
+		v.Errorf("sampling.initial", "must not be negative")
+	}
+	if c.Sampling.Thereafter < 0 {
+		v.Errorf("sampling.thereafter", "must not be negative")
+	}
+	if c.RateLimit < 0 {
+		v.Errorf("rate_limit", "must not be negative")
+	}
+}
+
+func (c ServerConfig) ValidateConfig(v *Validation) {
+	if c.Addr != "" {
+		if err := validateListenAddr(c.Addr); err != nil {
+			v.Errorf("addr", "%v", err)
+		}
+	}
+	for i, value := range c.IPTrustList {
+		if _, err := parseTrustedProxyIPRange(value); err != nil {
+			v.Errorf(fmt.Sprintf("ip_trust_list[%d]", i), "%v", err)
+		}
+	}
+	v.Validate("health", c.Health)
+	v.Validate("access_log", c.AccessLog)
+	v.Validate("admin", c.Admin)
+}
+
+func (c AdminConfig) ValidateConfig(v *Validation) {
+	if c.Enabled && c.LogLevelsPath != "" && !strings.HasPrefix(c.LogLevelsPath, "/") {
+		v.Errorf("log_levels_path", "must start with /")
+	}
+}
+
+func (c AccessLogConfig) ValidateConfig(v *Validation) {
+	for i, field := range c.Fields {
+		v.OneOf(fmt.Sprintf("fields[%d]", i), field, accessLogFields...)
+	}
+	if c.SlowThreshold < 0 {
+		v.Errorf("slow_threshold", "must not be negative")
+	}
+	if c.Sampling < 0 || c.Sampling > 1 {
+		v.Errorf("sampling", "must be between 0 and 1, got %v", c.Sampling)
+	}
+	if c.MaxSize < 0 {
+		v.Errorf("max_size", "must not be negative")
+	}
+	if c.MaxAge < 0 {
+		v.Errorf("max_age", "must not be negative")
+	}
+}
+
+func (c HealthConfig) ValidateConfig(v *Validation) {
+	if !c.Enabled {
+		return
+	}
+	// 路径为空时不注册对应的探针
+	if c.LivenessPath != "" && !strings.HasPrefix(c.LivenessPath, "/") {
+		v.Errorf("liveness_path", "must start with /")
+	}
+	if c.ReadinessPath != "" && !strings.HasPrefix(c.ReadinessPath, "/") {
+		v.Errorf("readiness_path", "must start with /")
+	}
+	if c.LivenessPath != "" && c.LivenessPath == c.ReadinessPath {
+		v.Errorf("readiness_path", "must differ from liveness_path")
+	}
+	if c.Timeout < 0 {
+		v.Errorf("timeout", "must not be negative")
+	}
+	if c.DrainDelay < 0 {
+		v.Errorf("drain_delay", "must not be negative")
+	}
+}
+
+func (c DatabaseConfig) ValidateConfig(v *Validation) {
+	v.Required("type", string(c.Type))
+
+	if c.URL == "" {
+		switch c.Type {
+		case DatabaseMysql:
+			v.Required("mysql.hostname", c.Mysql.Hostname)
+			v.Range("mysql.port", c.Mysql.Port, 1, 65535)
+			v.Required("mysql.database", c.Mysql.Database)
+		case DatabasePostgres, DatabasePostgresql:
+			v.Required("postgres.hostname", c.Postgres.Hostname)
+			v.Range("postgres.port", c.Postgres.Port, 1, 65535)
+			v.Required("postgres.database", c.Postgres.Database)
+		}
+	}
+
+	if _, err := normalizeReplicaPolicy(c.ReplicaPolicy); err != nil {
+		v.Errorf("replica_policy", "must be one of %s, %s, got %q", ReplicaPolicyRandom, ReplicaPolicyRoundRobin, c.ReplicaPolicy)
+	}
+	v.OneOf("schema_check", c.SchemaCheck, SchemaCheckOff, SchemaCheckWarn, SchemaCheckFail)
+
+	for i, replica := range c.Replicas {
+		field := fmt.Sprintf("replicas[%d]", i)
+		if len(replica.Replicas) > 0 {
+			v.Errorf(field+".replicas", "replicas cannot have replicas")
+		}
+		if replica.Type == "" {
+			replica.Type = c.Type
+		}
+		v.Validate(field, replica)
+	}
+}
+
+// validateListenAddr 校验 host:port 形式的监听地址
+func validateListenAddr(addr string) error {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("must be host:port, got %q", addr)
+	}
+	if _, err := net.LookupPort("tcp", port); err != nil {
+		return fmt.Errorf("invalid port %q", port)
+	}
+	return nil
+}
+
+// appConfigValidator 应用自定义配置段的校验规则
+type appConfigValidator struct {
+	path   string
+	target reflect.Type
+}
+
+// AddConfigValidator 为 app 配置段注册校验规则
+// target 为实现 ConfigValidator 的结构体指针，例如 &PaymentConfig{}，
+// 校验时将 app.<path> 解析到 target 类型的新实例后调用 ValidateConfig；path 为空时解析整个 app 段。
+// 结构体类型的 target 同时按 BindAppConfig 绑定环境变量
+func (a *App) AddConfigValidator(path string, target ConfigValidator) {
+	targetType := reflect.TypeOf(target)
+	if targetType == nil || targetType.Kind() != reflect.Pointer {
+		panic("orz: AddConfigValidator requires a pointer target")
+	}
+
+	a.configValidators = append(a.configValidators, appConfigValidator{path: path, target: targetType.Elem()})
+	if isConfigSection(targetType.Elem()) {
+		a.BindAppConfig(path, target)
+	}
+}
+
+// WithConfigValidator 为 app 配置段注册校验规则，参见 App.AddConfigValidator
+func WithConfigValidator(path string, target ConfigValidator) Option {
+	return func(f *Framework) error {
+		f.app.AddConfigValidator(path, target)
+		return nil
+	}
+}
+
+// ValidateConfig 解析并校验当前配置，包括通过 AddConfigValidator 注册的 app 配置段
+func (a *App) ValidateConfig() error {
+	config, err := a.configManager.Decode()
+	if err != nil {
+		return err
+	}
+	return a.validateConfig(config)
+}
+
+func (a *App) validateConfig(config *Config) error {
+	v := NewValidation()
+	a.collectConfigErrors(v, config)
+	return v.Err()
+}
+
+// collectConfigErrors 执行框架配置与 app 配置段的校验规则
+func (a *App) collectConfigErrors(v *Validation, config *Config) {
+	config.ValidateConfig(v)
+
+	for _, validator := range a.configValidators {
+		field := joinConfigPath("app", validator.path)
+		target := reflect.New(validator.target)
+		if err := decodeAppConfig(config.App, validator.path, a.configManager, target.Interface()); err != nil {
+			v.Errorf(field, "%v", err)
+			continue
+		}
+		v.Validate(field, target.Interface().(ConfigValidator))
+	}
+}
+
+// lookupConfigPath 按点分隔的路径查找 app 配置段中的值，不存在时返回 nil
+func lookupConfigPath(section map[string]any, path string) any {
+	if path == "" {
+		return section
+	}
+
+	var current any = section
+	for _, part := range strings.Split(path, ".") {
+		values, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = nil
+		for key, value := range values {
+			if matchConfigName(key, part) {
+				current = value
+				break
+			}
+		}
+	}
+	return current
+}
+
+func joinConfigPath(parent, path string) string {
+	if path == "" {
+		return parent
+	}
+	return parent + "." + path
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,113 @@
+package orz
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+type paymentConfig struct {
+	Provider string
+	Timeout  int
+}
+
+func (c *paymentConfig) ValidateConfig(v *Validation) {
+	v.OneOf("provider", c.Provider, "stripe", "adyen")
+	v.Range("timeout", c.Timeout, 1, 60)
+}
+
+func TestConfigValidationReportsPathQualifiedErrors(t *testing.T) {
+	_, err := NewFramework(
+		WithLogger(zap.NewNop()),
+		WithConfigMap(map[string]interface{}{
+			"log": map[string]interface{}{"level": "verbose"},
+			"server": map[string]interface{}{
+				"addr":          "localhost",
+				"ip_trust_list": []string{"10.0.0.0/8", "not-an-ip"},
+			},
+			"database": map[string]interface{}{
+				"type":           "mysql",
+				"mysql":          map[string]interface{}{"hostname": "db", "port": 70000, "database": "app"},
+				"replica_policy": "nearest",
+				"replicas": []interface{}{
+					map[string]interface{}{"mysql": map[string]interface{}{"port": 3306, "database": "app"}},
+				},
+			},
+			"databases": map[string]interface{}{
+				"analytics": map[string]interface{}{"type": "postgres", "url": "postgres://db/analytics", "schema_check": "strict"},
+			},
+		}),
+	)
+
+	var validationErrors ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+
+	expected := []string{
+		"log.level",
+		"server.addr",
+		"server.ip_trust_list[1]",
+		"database.mysql.port: must be 1-65535",
+		"database.replica_policy",
+		"database.replicas[0].mysql.hostname: is required",
+		"databases.analytics.schema_check",
+	}
+	for _, path := range expected {
+		if !strings.Contains(err.Error(), path) {
+			t.Fatalf("expected error to mention %q, got: %v", path, err)
+		}
+	}
+	if len(validationErrors) != len(expected) {
+		t.Fatalf("expected %d errors, got %d: %v", len(expected), len(validationErrors), err)
+	}
+}
+
+func TestConfigValidationAcceptsDefaults(t *testing.T) {
+	config := NewConfigManager().GetConfig()
+	if err := config.Validate(); err != nil {
+		t.Fatalf("expected default config to be valid, got %v", err)
+	}
+}
+
+func TestConfigValidatorCoversAppSection(t *testing.T) {
+	newFramework := func(payments map[string]interface{}) error {
+		_, err := NewFramework(
+			WithLogger(zap.NewNop()),
+			WithConfigValidator("payments", &paymentConfig{}),
+			WithConfigMap(map[string]interface{}{
+				"app": map[string]interface{}{"payments": payments},
+			}),
+		)
+		return err
+	}
+
+	if err := newFramework(map[string]interface{}{"provider": "stripe", "timeout": 30}); err != nil {
+		t.Fatalf("expected valid app section, got %v", err)
+	}
+
+	err := newFramework(map[string]interface{}{"provider": "paypal", "timeout": 0})
+	if err == nil {
+		t.Fatal("expected app section validation to fail")
+	}
+	for _, path := range []string{"app.payments.provider", "app.payments.timeout: must be 1-60"} {
+		if !strings.Contains(err.Error(), path) {
+			t.Fatalf("expected error to mention %q, got: %v", path, err)
+		}
+	}
+}
+
+func TestReloadConfigRejectsInvalidConfig(t *testing.T) {
+	app, path, _ := newReloadableApp(t, "log:\n  level: info\n")
+
+	writeConfigFile(t, path, "log:\n  level: loud\n")
+	err := app.ReloadConfig()
+	if err == nil || !strings.Contains(err.Error(), "log.level") {
+		t.Fatalf("expected reload to fail validation, got %v", err)
+	}
+	if app.GetConfig().Log.Level != "info" {
+		t.Fatal("expected previous config to be kept")
+	}
+}
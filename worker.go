@@ -0,0 +1,249 @@
+package orz
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultWorkerDrainTimeout 停止阶段等待后台任务退出的最长时间
+	defaultWorkerDrainTimeout = 30 * time.Second
+	defaultRestartBackoff     = time.Second
+	defaultMaxRestartBackoff  = 30 * time.Second
+)
+
+// Worker 由 App 托管的后台任务
+// Run 应在 ctx 取消后尽快返回
+type Worker interface {
+	Run(ctx context.Context) error
+}
+
+// WorkerFunc 函数形式的后台任务
+type WorkerFunc func(ctx context.Context) error
+
+// Run 执行后台任务
+func (f WorkerFunc) Run(ctx context.Context) error {
+	return f(ctx)
+}
+
+// RestartPolicy 后台任务重启策略
+type RestartPolicy uint8
+
+const (
+	RestartNever     RestartPolicy = iota // 退出后不再重启
+	RestartOnFailure                      // 返回错误或 panic 时按退避时间重启
+)
+
+// WorkerOption 后台任务选项
+type WorkerOption func(*workerSpec)
+
+// WithRestartPolicy 设置重启策略，默认不重启
+func WithRestartPolicy(policy RestartPolicy) WorkerOption {
+	return func(s *workerSpec) {
+		s.policy = policy
+	}
+}
+
+// WithRestartBackoff 设置重启退避时间，每次失败后翻倍直到 max
+func WithRestartBackoff(initial, max time.Duration) WorkerOption {
+	return func(s *workerSpec) {
+		if initial > 0 {
+			s.backoff = initial
+		}
+		if max > 0 {
+			s.maxBackoff = max
+		}
+	}
+}
+
+// WithMaxRestarts 设置最大重启次数，0 表示不限制
+func WithMaxRestarts(n int) WorkerOption {
+	return func(s *workerSpec) {
+		s.maxRestarts = n
+	}
+}
+
+// WithCritical 标记为关键任务，最终失败时停止整个应用并由 Run 返回该错误
+func WithCritical() WorkerOption {
+	return func(s *workerSpec) {
+		s.critical = true
+	}
+}
+
+type workerSpec struct {
+	name        string
+	worker      Worker
+	policy      RestartPolicy
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	maxRestarts int
+	critical    bool
+}
+
+// workerGroup 管理后台任务的启动、监督与停止
+type workerGroup struct {
+	mu      sync.Mutex
+	specs   []workerSpec
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	stopped bool
+	failure error
+}
+
+// Go 以函数形式注册后台任务
+func (a *App) Go(name string, fn func(ctx context.Context) error, options ...WorkerOption) {
+	a.AddWorker(name, WorkerFunc(fn), options...)
+}
+
+// AddWorker 注册后台任务，Run 启动后注册的任务会立即运行
+func (a *App) AddWorker(name string, worker Worker, options ...WorkerOption) {
+	if worker == nil {
+		panic("orz: worker is nil")
+	}
+
+	spec := workerSpec{
+		name:       name,
+		worker:     worker,
+		backoff:    defaultRestartBackoff,
+		maxBackoff: defaultMaxRestartBackoff,
+	}
+	for _, option := range options {
+		option(&spec)
+	}
+
+	a.workers.mu.Lock()
+	defer a.workers.mu.Unlock()
+
+	if a.workers.stopped {
+		a.Logger().Warn("worker registered after shutdown, ignored", zap.String("worker", name))
+		return
+	}
+
+	a.workers.specs = append(a.workers.specs, spec)
+	if a.workers.ctx != nil {
+		a.launchWorker(spec)
+	}
+}
+
+// startWorkers 启动所有已注册的后台任务
+// 任务使用独立的上下文，在 HTTP 服务优雅关闭之后才会被取消
+func (a *App) startWorkers() {
+	a.workers.mu.Lock()
+	defer a.workers.mu.Unlock()
+
+	a.workers.ctx, a.workers.cancel = context.WithCancel(context.WithoutCancel(a.ctx))
+	for _, spec := range a.workers.specs {
+		a.launchWorker(spec)
+	}
+}
+
+// stopWorkers 取消后台任务并等待其退出
+func (a *App) stopWorkers() error {
+	a.workers.mu.Lock()
+	a.workers.stopped = true
+	cancel := a.workers.cancel
+	a.workers.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		a.workers.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(defaultWorkerDrainTimeout):
+		a.Logger().Error("workers did not stop in time", zap.Duration("timeout", defaultWorkerDrainTimeout))
+		return fmt.Errorf("workers did not stop within %s", defaultWorkerDrainTimeout)
+	}
+}
+
+// workerFailure 返回导致应用停止的关键任务错误
+func (a *App) workerFailure() error {
+	a.workers.mu.Lock()
+	defer a.workers.mu.Unlock()
+	return a.workers.failure
+}
+
+// launchWorker 需在持有 workers.mu 时调用
+func (a *App) launchWorker(spec workerSpec) {
+	ctx := a.workers.ctx
+	a.workers.wg.Add(1)
+	go func() {
+		defer a.workers.wg.Done()
+		if err := a.superviseWorker(ctx, spec); err != nil && spec.critical {
+			a.workers.mu.Lock()
+			if a.workers.failure == nil {
+				a.workers.failure = fmt.Errorf("worker %q failed: %w", spec.name, err)
+			}
+			a.workers.mu.Unlock()
+			a.Logger().Error("critical worker failed, shutting down", zap.String("worker", spec.name), zap.Error(err))
+			a.cancel()
+		}
+	}()
+}
+
+// superviseWorker 按重启策略运行任务，返回最终的失败原因
+func (a *App) superviseWorker(ctx context.Context, spec workerSpec) error {
+	log := a.Logger().With(zap.String("worker", spec.name))
+	backoff := spec.backoff
+	restarts := 0
+
+	for {
+		started := time.Now()
+		err := runWorker(ctx, spec.worker)
+		if ctx.Err() != nil {
+			log.Debug("worker stopped")
+			return nil
+		}
+		if err == nil {
+			log.Info("worker exited")
+			return nil
+		}
+
+		log.Error("worker failed", zap.Error(err))
+		if spec.policy != RestartOnFailure {
+			return err
+		}
+		if spec.maxRestarts > 0 && restarts >= spec.maxRestarts {
+			return fmt.Errorf("giving up after %d restarts: %w", restarts, err)
+		}
+
+		// 长时间稳定运行后重置退避时间
+		if time.Since(started) >= spec.maxBackoff {
+			backoff = spec.backoff
+		}
+
+		log.Info("restarting worker", zap.Duration("backoff", backoff))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+
+		restarts++
+		backoff = min(backoff*2, spec.maxBackoff)
+	}
+}
+
+// runWorker 执行任务并将 panic 转换为错误
+func runWorker(ctx context.Context, worker Worker) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return worker.Run(ctx)
+}
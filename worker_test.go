@@ -0,0 +1,91 @@
+package orz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestWorkerRestartsOnFailureAndRecoversPanic(t *testing.T) {
+	app := NewApp()
+	app.SetLogger(zap.NewNop())
+
+	var runs atomic.Int32
+	app.Go("flaky", func(ctx context.Context) error {
+		switch runs.Add(1) {
+		case 1:
+			panic("boom")
+		case 2:
+			return errors.New("temporary failure")
+		default:
+			app.cancel()
+			<-ctx.Done()
+			return nil
+		}
+	}, WithRestartPolicy(RestartOnFailure), WithRestartBackoff(time.Millisecond, 10*time.Millisecond))
+
+	if err := app.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if got := runs.Load(); got != 3 {
+		t.Fatalf("expected worker to run 3 times, got %d", got)
+	}
+}
+
+func TestCriticalWorkerFailureStopsApp(t *testing.T) {
+	app := NewApp()
+	app.SetLogger(zap.NewNop())
+
+	app.Go("consumer", func(ctx context.Context) error {
+		return errors.New("broker unreachable")
+	}, WithCritical())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- app.Run()
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), `worker "consumer" failed: broker unreachable`) {
+			t.Fatalf("expected critical worker error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run did not exit after critical worker failure")
+	}
+}
+
+func TestWorkersDrainBeforeStopHooks(t *testing.T) {
+	app := NewApp()
+	app.SetLogger(zap.NewNop())
+
+	var drained atomic.Bool
+	app.Go("loop", func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+		drained.Store(true)
+		return ctx.Err()
+	})
+	app.OnStart("cancel", func(ctx context.Context) error {
+		go app.cancel()
+		return nil
+	})
+
+	drainedBeforeStop := false
+	app.OnStop("flush", func(ctx context.Context) error {
+		drainedBeforeStop = drained.Load()
+		return nil
+	})
+
+	if err := app.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if !drainedBeforeStop {
+		t.Fatal("expected workers to drain before stop hooks run")
+	}
+}